@@ -0,0 +1,125 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package heap
+
+import "time"
+
+// An AgingFunc returns the effective priority of an element with base priority p that has waited for the
+// given duration. Lower priorities are popped first, so an AgingFunc should not increase as waited grows.
+type AgingFunc func(p float64, waited time.Duration) float64
+
+// LinearAging returns an AgingFunc that lowers the priority by rate for every unit of time waited.
+func LinearAging(rate float64, unit time.Duration) AgingFunc {
+	return func(p float64, waited time.Duration) float64 {
+		return p - rate*float64(waited)/float64(unit)
+	}
+}
+
+// StepAging returns an AgingFunc that lowers the priority by boost for every full step of time waited.
+func StepAging(step time.Duration, boost float64) AgingFunc {
+	return func(p float64, waited time.Duration) float64 {
+		return p - boost*float64(waited/step)
+	}
+}
+
+// An AgingQueue is a priority queue in which an element's effective priority improves the longer it waits,
+// so that low-priority elements are not starved.
+//
+// Effective priorities are recomputed for every element at most once per rekey interval, when an element is
+// popped. Rekeying takes O(n) time, so the cost is O(n) per rekey interval plus O(log n) per Pop. Between
+// rekeys, elements are ordered by the effective priorities they had at the last rekey or, if pushed since,
+// when they were pushed, so aging takes effect up to one interval late. Elements with equal effective
+// priorities are popped in the order they were pushed.
+type AgingQueue[T any] struct {
+	age      AgingFunc
+	interval time.Duration
+	clock    Clock
+
+	h       Heap[agingElement[T]]
+	seq     uint64
+	rekeyed time.Time
+}
+
+type agingElement[T any] struct {
+	v     T
+	base  float64
+	added time.Time
+	seq   uint64
+
+	// key is the effective priority as of the last rekey.
+	key float64
+}
+
+func (e agingElement[T]) Less(o agingElement[T]) bool {
+	if e.key == o.key {
+		return e.seq < o.seq
+	}
+	return e.key < o.key
+}
+
+// NewAgingQueue returns an empty AgingQueue that ages elements with age and recomputes effective priorities
+// at most once per interval, reading time from clock. A nil clock uses SystemClock. It panics if interval is
+// not positive.
+func NewAgingQueue[T any](age AgingFunc, interval time.Duration, clock Clock) *AgingQueue[T] {
+	if interval <= 0 {
+		panic("heap: aging interval must be positive")
+	}
+	if clock == nil {
+		clock = SystemClock{}
+	}
+	return &AgingQueue[T]{
+		age:      age,
+		interval: interval,
+		clock:    clock,
+		rekeyed:  clock.Now(),
+	}
+}
+
+// Len returns the number of elements in the queue.
+func (q *AgingQueue[T]) Len() int {
+	return q.h.Len()
+}
+
+// Push adds v to the queue with base priority p.
+func (q *AgingQueue[T]) Push(v T, p float64) {
+	now := q.clock.Now()
+	q.h.PushElement(agingElement[T]{
+		v:     v,
+		base:  p,
+		added: now,
+		seq:   q.seq,
+		key:   q.age(p, 0),
+	})
+	q.seq++
+}
+
+// Pop removes and returns the element with the lowest effective priority.
+func (q *AgingQueue[T]) Pop() (T, bool) {
+	if now := q.clock.Now(); now.Sub(q.rekeyed) >= q.interval {
+		q.rekey(now)
+	}
+	e, ok := q.h.PopElement()
+	return e.v, ok
+}
+
+// Peek returns the element with the lowest effective priority as of the last rekey.
+func (q *AgingQueue[T]) Peek() (T, bool) {
+	e, ok := q.h.PeekElement()
+	return e.v, ok
+}
+
+// Rekey recomputes the effective priority of every element in O(n) time.
+func (q *AgingQueue[T]) Rekey() {
+	q.rekey(q.clock.Now())
+}
+
+func (q *AgingQueue[T]) rekey(now time.Time) {
+	for i := range q.h {
+		e := &q.h[i]
+		e.key = q.age(e.base, now.Sub(e.added))
+	}
+	q.h.Init()
+	q.rekeyed = now
+}
@@ -0,0 +1,81 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package heap
+
+import (
+	"testing"
+	"time"
+)
+
+func mustPop(t *testing.T, q *AgingQueue[string], want string) {
+	t.Helper()
+	if got, ok := q.Pop(); !ok || got != want {
+		t.Fatalf("Pop() = %q, %t, want %q, true", got, ok, want)
+	}
+}
+
+func TestAgingQueueOvertake(t *testing.T) {
+	c := NewFakeClock(epoch)
+	q := NewAgingQueue[string](LinearAging(1, time.Second), 10*time.Second, c)
+	q.Push("low", 100)
+	c.Advance(30 * time.Second)
+	q.Push("high", 80)
+	q.Push("high2", 80)
+
+	// low has aged to 70 but is still keyed at 100 until the next rekey.
+	if got, _ := q.Peek(); got != "high" {
+		t.Fatalf("Peek() = %q before rekey, want high", got)
+	}
+	mustPop(t, q, "low")
+
+	// Within the interval, high2 is not rekeyed, so it keeps its push-time priority and FIFO order holds.
+	c.Advance(5 * time.Second)
+	mustPop(t, q, "high")
+	mustPop(t, q, "high2")
+	if _, ok := q.Pop(); ok {
+		t.Error("Pop() on empty queue reported true")
+	}
+}
+
+func TestAgingQueueStaleWithinInterval(t *testing.T) {
+	c := NewFakeClock(epoch)
+	q := NewAgingQueue[string](LinearAging(1, time.Second), time.Minute, c)
+	q.Push("low", 100)
+	c.Advance(30 * time.Second)
+	q.Push("new", 75)
+	mustPop(t, q, "new") // low has aged to 70, but no rekey is due yet
+
+	c.Advance(30 * time.Second)
+	q.Push("later", 45)
+	mustPop(t, q, "low") // the rekey ages low to 40
+	mustPop(t, q, "later")
+}
+
+func TestAgingQueueRekey(t *testing.T) {
+	c := NewFakeClock(epoch)
+	q := NewAgingQueue[string](StepAging(10*time.Second, 15), time.Hour, c)
+	q.Push("low", 100)
+	c.Advance(20 * time.Second)
+	q.Push("high", 75)
+	if got, _ := q.Peek(); got != "high" {
+		t.Fatalf("Peek() = %q, want high", got)
+	}
+	q.Rekey()
+	if got, _ := q.Peek(); got != "low" {
+		t.Fatalf("Peek() = %q after Rekey, want low", got)
+	}
+	if n := q.Len(); n != 2 {
+		t.Errorf("Len() = %d, want 2", n)
+	}
+}
+
+func TestAgingQueueInterval(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("NewAgingQueue with a zero interval did not panic")
+		}
+	}()
+	NewAgingQueue[string](LinearAging(1, time.Second), 0, nil)
+}
@@ -0,0 +1,36 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package heap
+
+import "slices"
+
+// PopN removes and returns up to n min elements in the heap, in order.
+func (h *Heap[T]) PopN(n int) []T {
+	return h.PopAppend(nil, n)
+}
+
+// PopAppend removes up to n min elements in the heap and appends them to dst in order, returning the
+// extended slice. It grows dst at most once.
+func (h *Heap[T]) PopAppend(dst []T, n int) []T {
+	n = min(n, h.Len())
+	if n <= 0 {
+		return dst
+	}
+	dst = slices.Grow(dst, n)
+	for range n {
+		dst = append(dst, h.MustPopElement())
+	}
+	return dst
+}
+
+// PopWhile removes and returns the min elements in the heap, in order, for as long as pred reports true for
+// the min element.
+func (h *Heap[T]) PopWhile(pred func(T) bool) []T {
+	var out []T
+	for h.Len() > 0 && pred((*h)[0]) {
+		out = append(out, h.MustPopElement())
+	}
+	return out
+}
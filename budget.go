@@ -0,0 +1,146 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package heap
+
+// A Sizer reports its cost in bytes for a BudgetHeap.
+type Sizer interface {
+	Size() int
+}
+
+// A BudgetHeap is a min-heap bounded by the total byte cost of its elements. When the cost exceeds the
+// budget, the greatest elements are evicted.
+//
+// The cost of an element is given by the size function passed to NewBudgetHeap, or by its Size method if no
+// function is passed. Elements are kept in both a min-heap and a max-heap, so Push, Pop and each eviction
+// take O(log n) time.
+type BudgetHeap[T Comparable[T]] struct {
+	sizeOf func(T) int
+	budget int
+	size   int
+	min    Heap[budgetMin[T]]
+	max    Heap[budgetMax[T]]
+}
+
+type budgetEntry[T Comparable[T]] struct {
+	v                  T
+	size               int
+	minIndex, maxIndex int
+}
+
+type budgetMin[T Comparable[T]] struct {
+	e *budgetEntry[T]
+}
+
+func (m budgetMin[T]) Less(o budgetMin[T]) bool {
+	return m.e.v.Less(o.e.v)
+}
+
+func (m budgetMin[T]) SetIndex(i int) {
+	m.e.minIndex = i
+}
+
+type budgetMax[T Comparable[T]] struct {
+	e *budgetEntry[T]
+}
+
+func (m budgetMax[T]) Less(o budgetMax[T]) bool {
+	return o.e.v.Less(m.e.v)
+}
+
+func (m budgetMax[T]) SetIndex(i int) {
+	m.e.maxIndex = i
+}
+
+// NewBudgetHeap returns an empty BudgetHeap that holds elements costing up to budget bytes in total. If
+// size is not nil, it reports the cost of an element; otherwise T must implement Sizer. The cost of an
+// element must not change while it is in the heap. NewBudgetHeap panics if size is nil and T does not
+// implement Sizer.
+func NewBudgetHeap[T Comparable[T]](budget int, size func(T) int) *BudgetHeap[T] {
+	if size == nil {
+		var zero T
+		if _, ok := any(zero).(Sizer); !ok {
+			panic("heap: BudgetHeap element is not a Sizer and no size function was given")
+		}
+		size = func(v T) int {
+			return any(v).(Sizer).Size()
+		}
+	}
+	return &BudgetHeap[T]{sizeOf: size, budget: budget}
+}
+
+// Len returns the number of elements in the heap.
+func (h *BudgetHeap[T]) Len() int {
+	return h.min.Len()
+}
+
+// Size returns the total cost of the elements in the heap.
+func (h *BudgetHeap[T]) Size() int {
+	return h.size
+}
+
+// Budget returns the maximum total cost of the elements in the heap.
+func (h *BudgetHeap[T]) Budget() int {
+	return h.budget
+}
+
+// SetBudget changes the budget, evicting the greatest elements until their total cost is within it. It
+// returns the evicted elements, greatest first.
+func (h *BudgetHeap[T]) SetBudget(budget int) []T {
+	h.budget = budget
+	return h.evict()
+}
+
+// PushElement adds an element to the heap, then evicts the greatest elements until the total cost is within
+// the budget. It returns the evicted elements, greatest first, which may include e itself.
+func (h *BudgetHeap[T]) PushElement(e T) []T {
+	be := &budgetEntry[T]{v: e, size: h.sizeOf(e)}
+	h.size += be.size
+	h.min.PushElement(budgetMin[T]{be})
+	h.max.PushElement(budgetMax[T]{be})
+	return h.evict()
+}
+
+// PopElement removes and returns the min element in the heap.
+func (h *BudgetHeap[T]) PopElement() (T, bool) {
+	m, ok := h.min.PopElement()
+	if !ok {
+		var zero T
+		return zero, false
+	}
+	h.max.RemoveElement(m.e.maxIndex)
+	h.size -= m.e.size
+	return m.e.v, true
+}
+
+// PeekElement returns the min element in the heap.
+func (h *BudgetHeap[T]) PeekElement() (T, bool) {
+	m, ok := h.min.PeekElement()
+	if !ok {
+		var zero T
+		return zero, false
+	}
+	return m.e.v, true
+}
+
+// PeekMax returns the max element in the heap, which is the next to be evicted.
+func (h *BudgetHeap[T]) PeekMax() (T, bool) {
+	m, ok := h.max.PeekElement()
+	if !ok {
+		var zero T
+		return zero, false
+	}
+	return m.e.v, true
+}
+
+func (h *BudgetHeap[T]) evict() []T {
+	var evicted []T
+	for h.size > h.budget && h.max.Len() > 0 {
+		m := h.max.MustPopElement()
+		h.min.RemoveElement(m.e.minIndex)
+		h.size -= m.e.size
+		evicted = append(evicted, m.e.v)
+	}
+	return evicted
+}
@@ -0,0 +1,71 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package heap
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestBudgetHeap(t *testing.T) {
+	h := NewBudgetHeap(10, func(e intElem) int { return int(e) })
+	for _, e := range []intElem{3, 1, 4} {
+		if ev := h.PushElement(e); len(ev) != 0 {
+			t.Fatalf("PushElement(%d) evicted %v", e, ev)
+		}
+	}
+	if ev := h.PushElement(5); !slices.Equal(ev, []intElem{5}) {
+		t.Errorf("PushElement(5) evicted %v, want [5]", ev)
+	}
+	if h.Size() != 8 || h.Len() != 3 {
+		t.Errorf("Size() = %d, Len() = %d, want 8 and 3", h.Size(), h.Len())
+	}
+	if e, _ := h.PeekMax(); e != 4 {
+		t.Errorf("PeekMax() = %d, want 4", e)
+	}
+	if ev := h.SetBudget(1); !slices.Equal(ev, []intElem{4, 3}) {
+		t.Errorf("SetBudget(1) evicted %v, want [4 3]", ev)
+	}
+	if e, ok := h.PopElement(); !ok || e != 1 {
+		t.Errorf("PopElement() = %d, %t, want 1, true", e, ok)
+	}
+	if _, ok := h.PopElement(); ok || h.Size() != 0 {
+		t.Errorf("PopElement() on empty heap reported %t with Size() = %d", ok, h.Size())
+	}
+}
+
+type sized string
+
+func (a sized) Less(b sized) bool {
+	return a < b
+}
+
+func (a sized) Size() int {
+	return len(a)
+}
+
+func TestBudgetHeapSizer(t *testing.T) {
+	h := NewBudgetHeap[sized](5, nil)
+	h.PushElement("ab")
+	h.PushElement("cd")
+	if ev := h.PushElement("e"); len(ev) != 0 {
+		t.Errorf("PushElement(e) evicted %v", ev)
+	}
+	if ev := h.PushElement("a"); !slices.Equal(ev, []sized{"e"}) {
+		t.Errorf("PushElement(a) evicted %v, want [e]", ev)
+	}
+	if h.Size() != 5 {
+		t.Errorf("Size() = %d, want 5", h.Size())
+	}
+}
+
+func TestBudgetHeapNeedsSize(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("NewBudgetHeap with neither a size function nor a Sizer did not panic")
+		}
+	}()
+	NewBudgetHeap[intElem](10, nil)
+}
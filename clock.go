@@ -0,0 +1,148 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package heap
+
+import (
+	"sync"
+	"time"
+)
+
+// A Clock provides the current time and timers. It allows time-driven types in this package to be tested
+// deterministically.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+
+	// NewTimer creates a Timer that sends the current time on its channel after at least duration d.
+	NewTimer(d time.Duration) Timer
+}
+
+// A Timer is a single event created by a Clock.
+type Timer interface {
+	// C returns the channel on which the time is delivered.
+	C() <-chan time.Time
+
+	// Stop prevents the Timer from firing. It reports whether the call stopped the timer.
+	Stop() bool
+}
+
+// SystemClock is a Clock backed by the time package.
+type SystemClock struct{}
+
+// Now implements Clock.Now.
+func (SystemClock) Now() time.Time {
+	return time.Now()
+}
+
+// NewTimer implements Clock.NewTimer.
+func (SystemClock) NewTimer(d time.Duration) Timer {
+	return systemTimer{time.NewTimer(d)}
+}
+
+type systemTimer struct {
+	t *time.Timer
+}
+
+func (t systemTimer) C() <-chan time.Time {
+	return t.t.C
+}
+
+func (t systemTimer) Stop() bool {
+	return t.t.Stop()
+}
+
+// A FakeClock is a Clock whose time only changes when Advance or Set is called.
+type FakeClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	timers Heap[*fakeTimer]
+}
+
+// NewFakeClock returns a FakeClock set to now.
+func NewFakeClock(now time.Time) *FakeClock {
+	return &FakeClock{now: now}
+}
+
+// Now implements Clock.Now.
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// NewTimer implements Clock.NewTimer.
+func (c *FakeClock) NewTimer(d time.Duration) Timer {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	t := &fakeTimer{c: c, at: c.now.Add(d), ch: make(chan time.Time, 1), index: -1}
+	if d <= 0 {
+		t.ch <- c.now
+		return t
+	}
+	c.timers.PushElement(t)
+	return t
+}
+
+// Advance moves the clock forward by d, firing any timers that become due.
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.set(c.now.Add(d))
+}
+
+// Set moves the clock to now, firing any timers that become due. Moving the clock backwards fires no timers.
+func (c *FakeClock) Set(now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.set(now)
+}
+
+// Timers returns the number of timers that are waiting to fire.
+func (c *FakeClock) Timers() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.timers.Len()
+}
+
+func (c *FakeClock) set(now time.Time) {
+	c.now = now
+	for {
+		t, ok := c.timers.PeekElement()
+		if !ok || t.at.After(now) {
+			return
+		}
+		c.timers.MustPopElement()
+		t.ch <- now
+	}
+}
+
+type fakeTimer struct {
+	c     *FakeClock
+	at    time.Time
+	ch    chan time.Time
+	index int
+}
+
+func (t *fakeTimer) SetIndex(i int) {
+	t.index = i
+}
+
+func (t *fakeTimer) Less(o *fakeTimer) bool {
+	return t.at.Before(o.at)
+}
+
+func (t *fakeTimer) C() <-chan time.Time {
+	return t.ch
+}
+
+func (t *fakeTimer) Stop() bool {
+	t.c.mu.Lock()
+	defer t.c.mu.Unlock()
+	if t.index < 0 {
+		return false
+	}
+	t.c.timers.RemoveElement(t.index)
+	return true
+}
@@ -0,0 +1,88 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package heap
+
+import "cmp"
+
+// A Comparator is a three-way comparison in the style of cmp.Compare: it returns a negative number if a
+// sorts before b, a positive number if a sorts after b, and zero otherwise.
+//
+// A Comparator can be passed directly to slices.SortFunc, and its Less method to NewFuncHeap and Prioritize.
+type Comparator[T any] func(a, b T) int
+
+// Compare is the Comparator for a Comparable type.
+func Compare[T Comparable[T]](a, b T) int {
+	switch {
+	case a.Less(b):
+		return -1
+	case b.Less(a):
+		return 1
+	default:
+		return 0
+	}
+}
+
+// By returns a Comparator that orders values by the key extracted from them, in ascending order.
+func By[T any, K cmp.Ordered](key func(T) K) Comparator[T] {
+	return func(a, b T) int {
+		return cmp.Compare(key(a), key(b))
+	}
+}
+
+// ThenBy returns a Comparator that orders values by c, and values that c considers equal by next.
+func (c Comparator[T]) ThenBy(next Comparator[T]) Comparator[T] {
+	return func(a, b T) int {
+		if r := c(a, b); r != 0 {
+			return r
+		}
+		return next(a, b)
+	}
+}
+
+// Reverse returns a Comparator with the opposite order to c.
+func (c Comparator[T]) Reverse() Comparator[T] {
+	return func(a, b T) int {
+		return c(b, a)
+	}
+}
+
+// Less reports whether a sorts before b.
+func (c Comparator[T]) Less(a, b T) bool {
+	return c(a, b) < 0
+}
+
+// NullsFirst returns a Comparator for pointers that orders nil before any other pointer, and compares the
+// values of other pointers with c.
+func NullsFirst[T any](c Comparator[T]) Comparator[*T] {
+	return func(a, b *T) int {
+		switch {
+		case a == nil && b == nil:
+			return 0
+		case a == nil:
+			return -1
+		case b == nil:
+			return 1
+		default:
+			return c(*a, *b)
+		}
+	}
+}
+
+// NullsLast returns a Comparator for pointers that orders nil after any other pointer, and compares the
+// values of other pointers with c.
+func NullsLast[T any](c Comparator[T]) Comparator[*T] {
+	return func(a, b *T) int {
+		switch {
+		case a == nil && b == nil:
+			return 0
+		case a == nil:
+			return 1
+		case b == nil:
+			return -1
+		default:
+			return c(*a, *b)
+		}
+	}
+}
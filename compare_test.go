@@ -0,0 +1,101 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package heap
+
+import (
+	"slices"
+	"testing"
+)
+
+type person struct {
+	name string
+	age  int
+}
+
+func TestComparator(t *testing.T) {
+	people := []person{
+		{"carol", 30}, {"alice", 25}, {"bob", 30}, {"dave", 25}, {"alice", 40},
+	}
+	byAge := By(func(p person) int { return p.age })
+	byName := By(func(p person) string { return p.name })
+
+	c := byAge.ThenBy(byName)
+	slices.SortFunc(people, c)
+	want := []person{{"alice", 25}, {"dave", 25}, {"bob", 30}, {"carol", 30}, {"alice", 40}}
+	if !slices.Equal(people, want) {
+		t.Errorf("By(age).ThenBy(name): got %v, want %v", people, want)
+	}
+
+	slices.SortFunc(people, c.Reverse())
+	slices.Reverse(want)
+	if !slices.Equal(people, want) {
+		t.Errorf("By(age).ThenBy(name).Reverse(): got %v, want %v", people, want)
+	}
+
+	// Reversing only the tie-breaker leaves the primary order alone.
+	slices.SortFunc(people, byAge.ThenBy(byName.Reverse()))
+	want = []person{{"dave", 25}, {"alice", 25}, {"carol", 30}, {"bob", 30}, {"alice", 40}}
+	if !slices.Equal(people, want) {
+		t.Errorf("By(age).ThenBy(By(name).Reverse()): got %v, want %v", people, want)
+	}
+
+	if !c.Less(person{"bob", 30}, person{"carol", 30}) || c.Less(person{"bob", 30}, person{"bob", 30}) {
+		t.Error("Less disagrees with the Comparator")
+	}
+}
+
+func TestCompare(t *testing.T) {
+	for _, tc := range []struct {
+		a, b intElem
+		want int
+	}{
+		{1, 2, -1},
+		{2, 1, 1},
+		{2, 2, 0},
+	} {
+		if got := Compare(tc.a, tc.b); got != tc.want {
+			t.Errorf("Compare(%d, %d) = %d, want %d", tc.a, tc.b, got, tc.want)
+		}
+	}
+}
+
+func TestNulls(t *testing.T) {
+	one, two, three := 1, 2, 3
+	ptrs := []*int{&three, nil, &one, nil, &two}
+	values := func(ps []*int) []int {
+		var vs []int
+		for _, p := range ps {
+			if p == nil {
+				vs = append(vs, -1)
+			} else {
+				vs = append(vs, *p)
+			}
+		}
+		return vs
+	}
+	asc := By(func(v int) int { return v })
+
+	for _, tc := range []struct {
+		name string
+		c    Comparator[*int]
+		want []int
+	}{
+		{"NullsFirst", NullsFirst(asc), []int{-1, -1, 1, 2, 3}},
+		{"NullsLast", NullsLast(asc), []int{1, 2, 3, -1, -1}},
+		{"NullsFirst(Reverse)", NullsFirst(asc.Reverse()), []int{-1, -1, 3, 2, 1}},
+		{"NullsLast(Reverse)", NullsLast(asc.Reverse()), []int{3, 2, 1, -1, -1}},
+		// Reversing the whole Comparator moves the nils too.
+		{"NullsFirst.Reverse", NullsFirst(asc).Reverse(), []int{3, 2, 1, -1, -1}},
+	} {
+		s := slices.Clone(ptrs)
+		slices.SortFunc(s, tc.c)
+		if got := values(s); !slices.Equal(got, tc.want) {
+			t.Errorf("%s: got %v, want %v", tc.name, got, tc.want)
+		}
+		if tc.c(nil, nil) != 0 {
+			t.Errorf("%s: two nils compare unequal", tc.name)
+		}
+	}
+}
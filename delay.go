@@ -0,0 +1,189 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package heap
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// A DelayQueue holds values until the time they are scheduled for. It is safe for concurrent use.
+type DelayQueue[T any] struct {
+	clock Clock
+
+	mu   sync.Mutex
+	h    Heap[delayEntry[T]]
+	seq  uint64
+	live int
+	wake chan struct{}
+}
+
+// A DelayItem is a handle to a value scheduled on a DelayQueue.
+type DelayItem[T any] struct {
+	v   T
+	seq uint64
+
+	// done is set once the item has been released or cancelled.
+	done bool
+}
+
+// Value returns the scheduled value.
+func (d *DelayItem[T]) Value() T {
+	return d.v
+}
+
+// delayEntry is the heap element for a DelayItem. Rescheduling pushes a new entry and leaves the old one
+// in the heap; an entry is stale when its seq no longer matches the item's.
+type delayEntry[T any] struct {
+	at   time.Time
+	seq  uint64
+	item *DelayItem[T]
+}
+
+func (e delayEntry[T]) Less(o delayEntry[T]) bool {
+	if e.at.Equal(o.at) {
+		return e.seq < o.seq
+	}
+	return e.at.Before(o.at)
+}
+
+func (e delayEntry[T]) stale() bool {
+	return e.item.done || e.item.seq != e.seq
+}
+
+// NewDelayQueue returns an empty DelayQueue that reads time from clock. A nil clock uses SystemClock.
+func NewDelayQueue[T any](clock Clock) *DelayQueue[T] {
+	if clock == nil {
+		clock = SystemClock{}
+	}
+	return &DelayQueue[T]{clock: clock}
+}
+
+// Len returns the number of scheduled values that have not been released or cancelled.
+func (q *DelayQueue[T]) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.live
+}
+
+// Schedule adds v to the queue, to be released at or after at. Values due at the same time are released in
+// the order they were scheduled.
+func (q *DelayQueue[T]) Schedule(v T, at time.Time) *DelayItem[T] {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	d := &DelayItem[T]{v: v}
+	q.live++
+	q.push(d, at)
+	return d
+}
+
+// Reschedule changes the time at which d is released. It reports false if d was already released or
+// cancelled.
+func (q *DelayQueue[T]) Reschedule(d *DelayItem[T], at time.Time) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if d.done {
+		return false
+	}
+	q.push(d, at)
+	return true
+}
+
+// Cancel removes d from the queue. It reports false if d was already released or cancelled.
+func (q *DelayQueue[T]) Cancel(d *DelayItem[T]) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if d.done {
+		return false
+	}
+	d.done = true
+	q.live--
+	q.compact()
+	return true
+}
+
+// Next blocks until a value is due and returns it, or returns ctx.Err() if ctx is done first.
+func (q *DelayQueue[T]) Next(ctx context.Context) (T, error) {
+	for {
+		q.mu.Lock()
+		e, ok := q.peek()
+		now := q.clock.Now()
+		if ok && !e.at.After(now) {
+			q.h.MustPopElement()
+			e.item.done = true
+			q.live--
+			q.mu.Unlock()
+			return e.item.v, nil
+		}
+		if q.wake == nil {
+			q.wake = make(chan struct{})
+		}
+		wake := q.wake
+		q.mu.Unlock()
+
+		var (
+			t  Timer
+			tc <-chan time.Time
+		)
+		if ok {
+			t = q.clock.NewTimer(e.at.Sub(now))
+			tc = t.C()
+		}
+		select {
+		case <-ctx.Done():
+			if t != nil {
+				t.Stop()
+			}
+			var zero T
+			return zero, ctx.Err()
+		case <-wake:
+			if t != nil {
+				t.Stop()
+			}
+		case <-tc:
+		}
+	}
+}
+
+// push adds an entry for d at the given time and wakes any waiters, since the earliest due time may have
+// changed. The caller must hold q.mu.
+func (q *DelayQueue[T]) push(d *DelayItem[T], at time.Time) {
+	q.seq++
+	d.seq = q.seq
+	q.h.PushElement(delayEntry[T]{at: at, seq: q.seq, item: d})
+	q.compact()
+	if q.wake != nil {
+		close(q.wake)
+		q.wake = nil
+	}
+}
+
+// peek discards stale entries and returns the earliest live one. The caller must hold q.mu.
+func (q *DelayQueue[T]) peek() (delayEntry[T], bool) {
+	for {
+		e, ok := q.h.PeekElement()
+		if !ok || !e.stale() {
+			return e, ok
+		}
+		q.h.MustPopElement()
+	}
+}
+
+// compact drops stale entries once they make up more than half of the heap. The caller must hold q.mu.
+func (q *DelayQueue[T]) compact() {
+	if q.h.Len() <= 2*q.live {
+		return
+	}
+	live := q.h[:0]
+	for _, e := range q.h {
+		if !e.stale() {
+			live = append(live, e)
+		}
+	}
+	clear(q.h[len(live):])
+	q.h = live
+	q.h.Init()
+}
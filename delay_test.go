@@ -0,0 +1,197 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package heap
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+var epoch = time.Unix(1_700_000_000, 0)
+
+type nextResult struct {
+	v   string
+	err error
+}
+
+// startNext calls q.Next in a new goroutine and waits until it is blocked on a timer from c, so that a
+// following c.Advance is seen by it.
+func startNext(t *testing.T, ctx context.Context, q *DelayQueue[string], c *FakeClock, timers int) <-chan nextResult {
+	t.Helper()
+	ch := make(chan nextResult, 1)
+	go func() {
+		v, err := q.Next(ctx)
+		ch <- nextResult{v, err}
+	}()
+	waitTimers(t, c, timers)
+	return ch
+}
+
+// waitTimers waits until c has n timers waiting to fire.
+func waitTimers(t *testing.T, c *FakeClock, n int) {
+	t.Helper()
+	deadline := time.Now().Add(10 * time.Second)
+	for c.Timers() != n {
+		if time.Now().After(deadline) {
+			t.Fatalf("FakeClock has %d timers, want %d", c.Timers(), n)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func receive(t *testing.T, ch <-chan nextResult) nextResult {
+	t.Helper()
+	select {
+	case r := <-ch:
+		return r
+	case <-time.After(10 * time.Second):
+		t.Fatal("Next did not return")
+		panic("unreachable")
+	}
+}
+
+func mustNext(t *testing.T, q *DelayQueue[string], want string) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if got, err := q.Next(ctx); err != nil || got != want {
+		t.Fatalf("Next() = %q, %v, want %q, nil", got, err, want)
+	}
+}
+
+func TestDelayQueueSchedule(t *testing.T) {
+	c := NewFakeClock(epoch)
+	q := NewDelayQueue[string](c)
+	q.Schedule("b", epoch.Add(2*time.Second))
+	q.Schedule("a", epoch.Add(time.Second))
+	q.Schedule("c", epoch.Add(2*time.Second))
+	q.Schedule("now", epoch)
+	if n := q.Len(); n != 4 {
+		t.Fatalf("Len() = %d, want 4", n)
+	}
+
+	mustNext(t, q, "now")
+	ch := startNext(t, context.Background(), q, c, 1)
+	c.Advance(time.Second)
+	if r := receive(t, ch); r.v != "a" || r.err != nil {
+		t.Fatalf("Next() = %q, %v, want a", r.v, r.err)
+	}
+
+	c.Advance(time.Second)
+	// Values due at the same time are released in the order they were scheduled.
+	mustNext(t, q, "b")
+	mustNext(t, q, "c")
+	if n := q.Len(); n != 0 {
+		t.Errorf("Len() = %d, want 0", n)
+	}
+}
+
+func TestDelayQueueReschedule(t *testing.T) {
+	c := NewFakeClock(epoch)
+	q := NewDelayQueue[string](c)
+	a := q.Schedule("a", epoch.Add(time.Second))
+	q.Schedule("b", epoch.Add(2*time.Second))
+	if !q.Reschedule(a, epoch.Add(3*time.Second)) {
+		t.Fatal("Reschedule reported false")
+	}
+	if n := q.Len(); n != 2 {
+		t.Fatalf("Len() = %d after Reschedule, want 2", n)
+	}
+
+	ch := startNext(t, context.Background(), q, c, 1)
+	// Next waits for b, skipping the entry for a's old time.
+	c.Advance(2 * time.Second)
+	if r := receive(t, ch); r.v != "b" {
+		t.Fatalf("Next() = %q, want b", r.v)
+	}
+
+	// Rescheduling wakes a waiter, since the earliest due time may have moved earlier.
+	ch = startNext(t, context.Background(), q, c, 1)
+	q.Reschedule(a, epoch)
+	if r := receive(t, ch); r.v != "a" || r.err != nil {
+		t.Fatalf("Next() = %q, %v, want a", r.v, r.err)
+	}
+	if q.Reschedule(a, epoch) {
+		t.Error("Reschedule of a released item reported true")
+	}
+}
+
+func TestDelayQueueCancel(t *testing.T) {
+	c := NewFakeClock(epoch)
+	q := NewDelayQueue[string](c)
+	a := q.Schedule("a", epoch.Add(time.Second))
+	b := q.Schedule("b", epoch.Add(2*time.Second))
+	if !q.Cancel(a) {
+		t.Fatal("Cancel reported false")
+	}
+	if q.Cancel(a) {
+		t.Error("second Cancel reported true")
+	}
+	if n := q.Len(); n != 1 {
+		t.Fatalf("Len() = %d, want 1", n)
+	}
+	if a.Value() != "a" {
+		t.Errorf("Value() = %q, want a", a.Value())
+	}
+
+	c.Advance(2 * time.Second)
+	mustNext(t, q, "b")
+	if q.Cancel(b) {
+		t.Error("Cancel of a released item reported true")
+	}
+}
+
+func TestDelayQueueCompacts(t *testing.T) {
+	q := NewDelayQueue[string](NewFakeClock(epoch))
+	var items []*DelayItem[string]
+	for i := range 100 {
+		items = append(items, q.Schedule("x", epoch.Add(time.Duration(i)*time.Second)))
+	}
+	for _, d := range items[1:] {
+		q.Cancel(d)
+	}
+	for i := range 100 {
+		q.Reschedule(items[0], epoch.Add(time.Duration(i)*time.Second))
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if n := q.h.Len(); n > 2 {
+		t.Errorf("heap holds %d entries for 1 item", n)
+	}
+}
+
+func TestDelayQueueNextContext(t *testing.T) {
+	c := NewFakeClock(epoch)
+	q := NewDelayQueue[string](c)
+	q.Schedule("a", epoch.Add(time.Hour))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := startNext(t, ctx, q, c, 1)
+	cancel()
+	if r := receive(t, ch); !errors.Is(r.err, context.Canceled) {
+		t.Fatalf("Next() error = %v, want context.Canceled", r.err)
+	}
+	waitTimers(t, c, 0) // the timer is stopped
+	if n := q.Len(); n != 1 {
+		t.Errorf("Len() = %d, want 1", n)
+	}
+}
+
+func TestDelayQueueNextEmpty(t *testing.T) {
+	c := NewFakeClock(epoch)
+	q := NewDelayQueue[string](c)
+	ch := make(chan nextResult, 1)
+	go func() {
+		v, err := q.Next(context.Background())
+		ch <- nextResult{v, err}
+	}()
+	// With nothing scheduled, Next waits without a timer until Schedule wakes it.
+	q.Schedule("a", epoch)
+	if r := receive(t, ch); r.v != "a" || r.err != nil {
+		t.Fatalf("Next() = %q, %v, want a", r.v, r.err)
+	}
+}
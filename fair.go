@@ -0,0 +1,144 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package heap
+
+// A FairQueue shares service between tenants using weighted fair queueing, while keeping each tenant's own
+// elements in priority order.
+//
+// Each element counts as one unit of work. A tenant with weight w is served w times as often as a tenant
+// with weight 1 while both have elements queued. Tenants are scheduled by virtual finish time, using the
+// self-clocked approximation of WFQ, so Push and Pop take O(log n) time.
+//
+// The zero value is an empty queue in which every tenant has weight 1.
+type FairQueue[Tenant comparable, T Comparable[T]] struct {
+	weights map[Tenant]float64
+
+	// active holds the tenants with queued elements, ordered by virtual finish time.
+	active  Heap[*fairTenant[Tenant, T]]
+	tenants map[Tenant]*fairTenant[Tenant, T]
+	vtime   float64
+	seq     uint64
+	n       int
+}
+
+type fairTenant[Tenant comparable, T Comparable[T]] struct {
+	id     Tenant
+	h      Heap[T]
+	weight float64
+	finish float64
+	seq    uint64
+}
+
+func (t *fairTenant[Tenant, T]) Less(o *fairTenant[Tenant, T]) bool {
+	if t.finish == o.finish {
+		return t.seq < o.seq
+	}
+	return t.finish < o.finish
+}
+
+// NewFairQueue returns an empty FairQueue in which every tenant has weight 1.
+func NewFairQueue[Tenant comparable, T Comparable[T]]() *FairQueue[Tenant, T] {
+	return &FairQueue[Tenant, T]{
+		weights: make(map[Tenant]float64),
+		tenants: make(map[Tenant]*fairTenant[Tenant, T]),
+	}
+}
+
+// SetWeight sets the weight of tenant t. It takes effect from the next element of t that is scheduled. It
+// panics if w is not positive.
+func (q *FairQueue[Tenant, T]) SetWeight(t Tenant, w float64) {
+	if !(w > 0) {
+		panic("heap: tenant weight must be positive")
+	}
+	if w == 1 {
+		delete(q.weights, t)
+	} else {
+		if q.weights == nil {
+			q.weights = make(map[Tenant]float64)
+		}
+		q.weights[t] = w
+	}
+	if ft, ok := q.tenants[t]; ok {
+		ft.weight = w
+	}
+}
+
+// Len returns the number of elements in the queue.
+func (q *FairQueue[Tenant, T]) Len() int {
+	return q.n
+}
+
+// TenantLen returns the number of elements queued for tenant t.
+func (q *FairQueue[Tenant, T]) TenantLen(t Tenant) int {
+	if ft, ok := q.tenants[t]; ok {
+		return ft.h.Len()
+	}
+	return 0
+}
+
+// Push adds v to the queue of tenant t.
+func (q *FairQueue[Tenant, T]) Push(t Tenant, v T) {
+	q.n++
+	if ft, ok := q.tenants[t]; ok {
+		ft.h.PushElement(v)
+		return
+	}
+	w, ok := q.weights[t]
+	if !ok {
+		w = 1
+	}
+	ft := &fairTenant[Tenant, T]{
+		id:     t,
+		weight: w,
+		finish: q.vtime + 1/w,
+		seq:    q.seq,
+	}
+	q.seq++
+	ft.h.PushElement(v)
+	if q.tenants == nil {
+		q.tenants = make(map[Tenant]*fairTenant[Tenant, T])
+	}
+	q.tenants[t] = ft
+	q.active.PushElement(ft)
+}
+
+// Pop removes and returns the min element of the tenant with the earliest virtual finish time, along with
+// the tenant.
+func (q *FairQueue[Tenant, T]) Pop() (Tenant, T, bool) {
+	ft, ok := q.active.PeekElement()
+	if !ok {
+		var (
+			zt Tenant
+			zv T
+		)
+		return zt, zv, false
+	}
+	v := ft.h.MustPopElement()
+	q.n--
+	q.vtime = ft.finish
+	if ft.h.Len() > 0 {
+		ft.finish += 1 / ft.weight
+		ft.seq = q.seq
+		q.seq++
+		q.active.Fix(0)
+	} else {
+		q.active.MustPopElement()
+		delete(q.tenants, ft.id)
+	}
+	return ft.id, v, true
+}
+
+// Peek returns the element that Pop would return, along with its tenant.
+func (q *FairQueue[Tenant, T]) Peek() (Tenant, T, bool) {
+	ft, ok := q.active.PeekElement()
+	if !ok {
+		var (
+			zt Tenant
+			zv T
+		)
+		return zt, zv, false
+	}
+	return ft.id, ft.h.MustPeekElement(), true
+}
@@ -0,0 +1,97 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package heap
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestFairQueueShares(t *testing.T) {
+	q := NewFairQueue[string, intElem]()
+	q.SetWeight("a", 3)
+	q.SetWeight("c", 1) // the default
+	for i := range 40 {
+		q.Push("a", intElem(i))
+		q.Push("b", intElem(i))
+	}
+	counts := make(map[string]int)
+	for range 40 {
+		tenant, _, ok := q.Pop()
+		if !ok {
+			t.Fatal("Pop() reported false")
+		}
+		counts[tenant]++
+	}
+	if counts["a"] < 29 || counts["a"] > 31 {
+		t.Errorf("served %v, want a 3:1 share", counts)
+	}
+	if n := q.Len(); n != 40 {
+		t.Errorf("Len() = %d, want 40", n)
+	}
+	if n := q.TenantLen("a") + q.TenantLen("b"); n != 40 {
+		t.Errorf("TenantLen sum = %d, want 40", n)
+	}
+}
+
+func TestFairQueueTenantOrder(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	var q FairQueue[int, intElem] // the zero value is usable
+	q.SetWeight(2, 2.5)
+	for range 1000 {
+		q.Push(r.Intn(4), intElem(r.Intn(100)))
+	}
+	last := make(map[int]intElem)
+	for q.Len() > 0 {
+		pt, pv, _ := q.Peek()
+		tenant, v, ok := q.Pop()
+		if !ok || tenant != pt || v != pv {
+			t.Fatalf("Pop() = %d, %d, %t, Peek() = %d, %d", tenant, v, ok, pt, pv)
+		}
+		if prev, ok := last[tenant]; ok && v < prev {
+			t.Fatalf("tenant %d popped %d after %d", tenant, v, prev)
+		}
+		last[tenant] = v
+	}
+	if _, _, ok := q.Pop(); ok {
+		t.Error("Pop() on empty queue reported true")
+	}
+	if n := q.TenantLen(0); n != 0 {
+		t.Errorf("TenantLen(0) = %d, want 0", n)
+	}
+}
+
+func TestFairQueueIdleTenant(t *testing.T) {
+	var q FairQueue[string, intElem]
+	for i := range 10 {
+		q.Push("busy", intElem(i))
+	}
+	for range 5 {
+		q.Pop()
+	}
+	// A tenant that was idle starts at the current virtual time, so it gets no backlog of credit.
+	q.Push("idle", 0)
+	q.Push("idle", 1)
+	var got []string
+	for q.Len() > 0 {
+		tenant, _, _ := q.Pop()
+		got = append(got, tenant)
+	}
+	want := []string{"busy", "idle", "busy", "idle", "busy", "busy", "busy"}
+	if !slices.Equal(got, want) {
+		t.Errorf("popped %v, want %v", got, want)
+	}
+}
+
+func TestFairQueueWeightPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("SetWeight(0) did not panic")
+		}
+	}()
+	var q FairQueue[string, intElem]
+	q.SetWeight("a", 0)
+}
@@ -0,0 +1,105 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package heap
+
+import "math/bits"
+
+// RemoveFunc removes the elements for which pred reports true and returns them in unspecified order. pred is
+// called once for each element.
+//
+// If only a few elements are removed, each is removed individually in O(log n) time. Otherwise the remaining
+// elements are compacted and the heap is rebuilt in O(n) time.
+func (h *Heap[T]) RemoveFunc(pred func(T) bool) []T {
+	n := h.Len()
+	marked := make([]bool, n)
+	k := 0
+	for i, e := range *h {
+		if pred(e) {
+			marked[i] = true
+			k++
+		}
+	}
+	if k == 0 {
+		return nil
+	}
+	removed := make([]T, 0, k)
+	if k*bits.Len(uint(n)) >= n {
+		s := *h
+		kept := s[:0]
+		for i, e := range s {
+			if marked[i] {
+				removed = append(removed, e)
+			} else {
+				kept = append(kept, e)
+			}
+		}
+		clear(s[len(kept):])
+		*h = kept
+		h.Init()
+	} else {
+		// Remove marked elements from the back so that the elements after i have all been visited. Sifting
+		// the replacement up may bring an unvisited, possibly marked, element down to i, so marks move
+		// with their elements and i is only advanced once the element at i is kept.
+		for i := n - 1; i >= 0; {
+			if i >= h.Len() || !marked[i] {
+				i--
+				continue
+			}
+			last := h.Len() - 1
+			removed = append(removed, (*h)[i])
+			(*h)[i] = (*h)[last]
+			marked[i] = false
+			var zero T
+			(*h)[last] = zero
+			*h = (*h)[:last]
+			if i == last {
+				continue
+			}
+			if isIndexed[T]() {
+				setIndex((*h)[i], i)
+			}
+			if !siftDown(*h, i, last, h.ops()) {
+				h.upMarked(i, marked)
+			}
+		}
+	}
+	if isIndexed[T]() {
+		for _, e := range removed {
+			any(e).(Indexed).SetIndex(-1)
+		}
+	}
+	return removed
+}
+
+// Retain removes the elements for which pred reports false. It is equivalent to RemoveFunc with the
+// opposite predicate.
+func (h *Heap[T]) Retain(pred func(T) bool) {
+	h.RemoveFunc(func(e T) bool {
+		return !pred(e)
+	})
+}
+
+// UpdateFunc calls f with a pointer to the element at index i, then re-establishes the heap ordering. f may
+// modify the element or replace it; an Indexed replacement is told its index.
+func (h *Heap[T]) UpdateFunc(i int, f func(*T)) {
+	f(&(*h)[i])
+	if isIndexed[T]() {
+		setIndex((*h)[i], i)
+	}
+	h.Fix(i)
+}
+
+// upMarked is up for RemoveFunc, moving each element's mark along with it.
+func (h *Heap[T]) upMarked(j int, marked []bool) {
+	for {
+		i := (j - 1) / 2 // parent
+		if i == j || !(*h)[j].Less((*h)[i]) {
+			break
+		}
+		h.Swap(i, j)
+		marked[i], marked[j] = marked[j], marked[i]
+		j = i
+	}
+}
@@ -0,0 +1,92 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package heap
+
+// A FuncHeap is a min-heap ordered by a less function rather than a method on its elements.
+type FuncHeap[T any] struct {
+	less func(a, b T) bool
+	s    []T
+}
+
+// NewFuncHeap returns an empty FuncHeap ordered by less, which reports whether a must sort before b.
+func NewFuncHeap[T any](less func(a, b T) bool) *FuncHeap[T] {
+	return &FuncHeap[T]{less: less}
+}
+
+// Len returns the number of elements in the heap.
+func (h *FuncHeap[T]) Len() int {
+	return len(h.s)
+}
+
+// At returns the element at index i.
+func (h *FuncHeap[T]) At(i int) T {
+	return h.s[i]
+}
+
+// Set replaces the element at index i with v and re-establishes the heap ordering.
+func (h *FuncHeap[T]) Set(i int, v T) {
+	h.s[i] = v
+	h.Fix(i)
+}
+
+// Fix re-establishes the heap ordering after the element at index i has changed its value.
+func (h *FuncHeap[T]) Fix(i int) {
+	if !siftDown(h.s, i, len(h.s), h.ops()) {
+		siftUp(h.s, i, h.ops())
+	}
+}
+
+// PushElement adds an element to the heap.
+func (h *FuncHeap[T]) PushElement(e T) {
+	h.s = append(h.s, e)
+	siftUp(h.s, len(h.s)-1, h.ops())
+}
+
+// MustPopElement removes and returns the min element in the heap. It panics if no elements are in the heap.
+func (h *FuncHeap[T]) MustPopElement() T {
+	return h.RemoveElement(0)
+}
+
+// PopElement removes and returns the min element in the heap.
+func (h *FuncHeap[T]) PopElement() (T, bool) {
+	if len(h.s) == 0 {
+		var zero T
+		return zero, false
+	}
+	return h.MustPopElement(), true
+}
+
+// MustPeekElement returns the min element in the heap. It panics if no elements are in the heap.
+func (h *FuncHeap[T]) MustPeekElement() T {
+	return h.s[0]
+}
+
+// PeekElement returns the min element in the heap.
+func (h *FuncHeap[T]) PeekElement() (T, bool) {
+	if len(h.s) == 0 {
+		var zero T
+		return zero, false
+	}
+	return h.s[0], true
+}
+
+// RemoveElement removes and returns the element at index i from the heap.
+func (h *FuncHeap[T]) RemoveElement(i int) T {
+	last := len(h.s) - 1
+	e := h.s[i]
+	h.s[i] = h.s[last]
+	var zero T
+	h.s[last] = zero
+	h.s = h.s[:last]
+	if i != last {
+		h.Fix(i)
+	}
+	return e
+}
+
+// ops returns the siftOps that order h.
+func (h *FuncHeap[T]) ops() siftOps[T] {
+	return siftOps[T]{less: h.less}
+}
@@ -0,0 +1,39 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package heap
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestFuncHeap(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	h := NewFuncHeap(func(a, b int) bool { return a > b })
+	var want []int
+	for range 500 {
+		v := r.Intn(100)
+		h.PushElement(v)
+		want = append(want, v)
+	}
+	for i := 0; i < h.Len(); i += 7 {
+		h.Set(i, r.Intn(100))
+	}
+	want = want[:0]
+	for i := range h.Len() {
+		want = append(want, h.At(i))
+	}
+	slices.Sort(want)
+	slices.Reverse(want)
+	for i, w := range want {
+		if got := h.MustPopElement(); got != w {
+			t.Fatalf("pop %d = %d, want %d", i, got, w)
+		}
+	}
+	if _, ok := h.PopElement(); ok {
+		t.Error("PopElement on empty heap reported true")
+	}
+}
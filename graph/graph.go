@@ -0,0 +1,94 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package graph implements heap-based graph search algorithms over generic weighted graphs.
+package graph
+
+import "github.com/iangudger/heap"
+
+// A Weight is a numeric edge weight. Algorithms in this package require weights to be non-negative.
+type Weight interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64
+}
+
+// A Graph is a directed graph with weighted edges.
+type Graph[N comparable, W Weight] interface {
+	// Neighbors calls yield for each edge leaving n, stopping early if yield returns false.
+	Neighbors(n N, yield func(to N, w W) bool)
+}
+
+// A Path is a sequence of nodes connected by edges.
+type Path[N comparable, W Weight] struct {
+	// Nodes holds the nodes of the path in order, including both endpoints.
+	Nodes []N
+
+	// Cost is the sum of the weights of the edges in the path.
+	Cost W
+}
+
+// item is a heap element holding a node and its priority.
+type item[N comparable, W Weight] struct {
+	node N
+	prio W
+}
+
+func (i item[N, W]) Less(o item[N, W]) bool {
+	return i.prio < o.prio
+}
+
+// pathTo builds the path ending at dst by following prev links back to src.
+func pathTo[N comparable, W Weight](prev map[N]N, src, dst N, cost W) Path[N, W] {
+	nodes := []N{dst}
+	for n := dst; n != src; {
+		n = prev[n]
+		nodes = append(nodes, n)
+	}
+	for i, j := 0, len(nodes)-1; i < j; i, j = i+1, j-1 {
+		nodes[i], nodes[j] = nodes[j], nodes[i]
+	}
+	return Path[N, W]{Nodes: nodes, Cost: cost}
+}
+
+// search runs Dijkstra's algorithm from src, or A* if h is not nil. It stops once dst is settled, if dst is
+// not nil. It returns the distance to and the predecessor of every node reached.
+func search[N comparable, W Weight](g Graph[N, W], src N, dst *N, h func(N) W) (dist map[N]W, prev map[N]N) {
+	dist = map[N]W{src: 0}
+	prev = make(map[N]N)
+	done := make(map[N]bool)
+	prio := func(n N, d W) W {
+		if h == nil {
+			return d
+		}
+		return d + h(n)
+	}
+
+	var q heap.Heap[item[N, W]]
+	q.PushElement(item[N, W]{src, prio(src, 0)})
+	for q.Len() > 0 {
+		u := q.MustPopElement().node
+		if done[u] {
+			continue
+		}
+		done[u] = true
+		if dst != nil && u == *dst {
+			break
+		}
+		du := dist[u]
+		g.Neighbors(u, func(v N, w W) bool {
+			if done[v] {
+				return true
+			}
+			d := du + w
+			if old, ok := dist[v]; !ok || d < old {
+				dist[v] = d
+				prev[v] = u
+				q.PushElement(item[N, W]{v, prio(v, d)})
+			}
+			return true
+		})
+	}
+	return dist, prev
+}
@@ -0,0 +1,55 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package graph
+
+import "github.com/iangudger/heap"
+
+// An Edge is a weighted edge between two nodes.
+type Edge[N comparable, W Weight] struct {
+	From, To N
+	Weight   W
+}
+
+// edgeItem is a heap element holding a candidate edge for the spanning tree.
+type edgeItem[N comparable, W Weight] struct {
+	e Edge[N, W]
+}
+
+func (i edgeItem[N, W]) Less(o edgeItem[N, W]) bool {
+	return i.e.Weight < o.e.Weight
+}
+
+// MinimumSpanningTree returns the edges of a minimum spanning tree of the nodes reachable from root, and
+// their total weight, using Prim's algorithm. The graph must be undirected: every edge must also be present
+// in the opposite direction with the same weight.
+func MinimumSpanningTree[N comparable, W Weight](g Graph[N, W], root N) ([]Edge[N, W], W) {
+	var (
+		tree  []Edge[N, W]
+		total W
+		q     heap.Heap[edgeItem[N, W]]
+	)
+	in := map[N]bool{root: true}
+	add := func(u N) {
+		g.Neighbors(u, func(v N, w W) bool {
+			if !in[v] {
+				q.PushElement(edgeItem[N, W]{Edge[N, W]{u, v, w}})
+			}
+			return true
+		})
+	}
+
+	add(root)
+	for q.Len() > 0 {
+		e := q.MustPopElement().e
+		if in[e.To] {
+			continue
+		}
+		in[e.To] = true
+		tree = append(tree, e)
+		total += e.Weight
+		add(e.To)
+	}
+	return tree, total
+}
@@ -0,0 +1,52 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package graph
+
+import "testing"
+
+func TestMinimumSpanningTree(t *testing.T) {
+	// The graph from Cormen et al., "Introduction to Algorithms", Figure 23.1, with nodes a to i numbered 0
+	// to 8, plus an edge between 9 and 10, which are not reachable from the others.
+	g := make(adj)
+	for _, e := range []Edge[int, int]{
+		{0, 1, 4}, {0, 7, 8}, {1, 7, 11}, {1, 2, 8}, {2, 8, 2}, {2, 5, 4}, {2, 3, 7},
+		{3, 5, 14}, {3, 4, 9}, {4, 5, 10}, {5, 6, 2}, {6, 8, 6}, {6, 7, 1}, {7, 8, 7},
+		{9, 10, 1},
+	} {
+		g.add(e.From, e.To, e.Weight)
+		g.add(e.To, e.From, e.Weight)
+	}
+
+	for root := range 9 {
+		tree, total := MinimumSpanningTree[int, int](g, root)
+		if total != 37 {
+			t.Errorf("root %d: total weight = %d, want 37", root, total)
+		}
+		if len(tree) != 8 {
+			t.Fatalf("root %d: %d edges, want 8", root, len(tree))
+		}
+		// The tree is grown from the root, so each edge must lead from a node already in it to a new one.
+		in := map[int]bool{root: true}
+		sum := 0
+		for _, e := range tree {
+			if w, ok := g[e.From][e.To]; !ok || w != e.Weight {
+				t.Fatalf("root %d: edge %v is not in the graph", root, e)
+			}
+			if !in[e.From] || in[e.To] {
+				t.Fatalf("root %d: edge %v does not extend the tree %v", root, e, in)
+			}
+			in[e.To] = true
+			sum += e.Weight
+		}
+		if sum != total {
+			t.Errorf("root %d: edges sum to %d, total is %d", root, sum, total)
+		}
+	}
+
+	tree, total := MinimumSpanningTree[int, int](g, 9)
+	if want := (Edge[int, int]{9, 10, 1}); len(tree) != 1 || tree[0] != want || total != 1 {
+		t.Errorf("MinimumSpanningTree(9) = %v, %d, want [%v], 1", tree, total, want)
+	}
+}
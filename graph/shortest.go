@@ -0,0 +1,223 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package graph
+
+import (
+	"slices"
+
+	"github.com/iangudger/heap"
+)
+
+// Dijkstra computes the shortest distance from src to every node reachable from it. It also returns the
+// predecessor of each reachable node other than src on a shortest path.
+func Dijkstra[N comparable, W Weight](g Graph[N, W], src N) (dist map[N]W, prev map[N]N) {
+	return search(g, src, nil, nil)
+}
+
+// ShortestPath returns a shortest path from src to dst. It reports false if dst is not reachable.
+func ShortestPath[N comparable, W Weight](g Graph[N, W], src, dst N) (Path[N, W], bool) {
+	return AStar(g, src, dst, nil)
+}
+
+// AStar returns a shortest path from src to dst using A* search. The heuristic h estimates the distance from
+// a node to dst; it must be consistent, never overestimating the cost of an edge plus the estimate for the
+// node it leads to. A nil h is equivalent to Dijkstra's algorithm. AStar reports false if dst is not
+// reachable.
+func AStar[N comparable, W Weight](g Graph[N, W], src, dst N, h func(N) W) (Path[N, W], bool) {
+	dist, prev := search(g, src, &dst, h)
+	d, ok := dist[dst]
+	if !ok {
+		return Path[N, W]{}, false
+	}
+	return pathTo(prev, src, dst, d), true
+}
+
+// BidirectionalDijkstra returns a shortest path from src to dst by searching forwards from src in g and
+// backwards from dst in rev, which must be g with every edge reversed. For an undirected graph, pass g as
+// both arguments. It reports false if dst is not reachable.
+func BidirectionalDijkstra[N comparable, W Weight](g, rev Graph[N, W], src, dst N) (Path[N, W], bool) {
+	if src == dst {
+		return Path[N, W]{Nodes: []N{src}}, true
+	}
+
+	type side struct {
+		g    Graph[N, W]
+		dist map[N]W
+		prev map[N]N
+		done map[N]bool
+		q    heap.Heap[item[N, W]]
+	}
+	fwd := &side{g: g, dist: map[N]W{src: 0}, prev: make(map[N]N), done: make(map[N]bool)}
+	bwd := &side{g: rev, dist: map[N]W{dst: 0}, prev: make(map[N]N), done: make(map[N]bool)}
+	fwd.q.PushElement(item[N, W]{src, 0})
+	bwd.q.PushElement(item[N, W]{dst, 0})
+
+	var (
+		found bool
+		best  W
+		meet  N
+	)
+	for fwd.q.Len() > 0 && bwd.q.Len() > 0 {
+		ftop, btop := fwd.q.MustPeekElement().prio, bwd.q.MustPeekElement().prio
+		if found && ftop+btop >= best {
+			break
+		}
+		s, o := fwd, bwd
+		if btop < ftop {
+			s, o = bwd, fwd
+		}
+		u := s.q.MustPopElement().node
+		if s.done[u] {
+			continue
+		}
+		s.done[u] = true
+		du := s.dist[u]
+		s.g.Neighbors(u, func(v N, w W) bool {
+			d := du + w
+			if old, ok := s.dist[v]; !ok || d < old {
+				s.dist[v] = d
+				s.prev[v] = u
+				s.q.PushElement(item[N, W]{v, d})
+			}
+			if od, ok := o.dist[v]; ok {
+				if c := s.dist[v] + od; !found || c < best {
+					found, best, meet = true, c, v
+				}
+			}
+			return true
+		})
+	}
+	if !found {
+		return Path[N, W]{}, false
+	}
+
+	p := pathTo(fwd.prev, src, meet, best)
+	for n := meet; n != dst; {
+		n = bwd.prev[n]
+		p.Nodes = append(p.Nodes, n)
+	}
+	return p, true
+}
+
+// KShortestPaths returns up to k shortest loopless paths from src to dst in order of increasing cost, using
+// Yen's algorithm.
+func KShortestPaths[N comparable, W Weight](g Graph[N, W], src, dst N, k int) []Path[N, W] {
+	if k <= 0 {
+		return nil
+	}
+	first, ok := ShortestPath(g, src, dst)
+	if !ok {
+		return nil
+	}
+	paths := []Path[N, W]{first}
+
+	var candidates heap.Heap[candidate[N, W]]
+	for len(paths) < k {
+		last := paths[len(paths)-1].Nodes
+		var rootCost W
+		for i := 0; i < len(last)-1; i++ {
+			if i > 0 {
+				rootCost += edgeWeight(g, last[i-1], last[i])
+			}
+			root := last[:i+1]
+			f := &filtered[N, W]{
+				g:     g,
+				nodes: make(map[N]bool),
+				edges: make(map[[2]N]bool),
+			}
+			for _, n := range root[:i] {
+				f.nodes[n] = true
+			}
+			for _, p := range paths {
+				if len(p.Nodes) > i+1 && slices.Equal(p.Nodes[:i+1], root) {
+					f.edges[[2]N{p.Nodes[i], p.Nodes[i+1]}] = true
+				}
+			}
+
+			spur, ok := ShortestPath[N, W](f, last[i], dst)
+			if !ok {
+				continue
+			}
+			nodes := make([]N, 0, i+len(spur.Nodes))
+			nodes = append(nodes, root[:i]...)
+			nodes = append(nodes, spur.Nodes...)
+			p := Path[N, W]{Nodes: nodes, Cost: rootCost + spur.Cost}
+			if !containsPath(paths, p) && !containsCandidate(candidates, p) {
+				candidates.PushElement(candidate[N, W]{p})
+			}
+		}
+		c, ok := candidates.PopElement()
+		if !ok {
+			break
+		}
+		paths = append(paths, c.p)
+	}
+	return paths
+}
+
+// candidate is a heap element holding a possible next path in Yen's algorithm. Ties in cost are broken by
+// preferring fewer nodes.
+type candidate[N comparable, W Weight] struct {
+	p Path[N, W]
+}
+
+func (c candidate[N, W]) Less(o candidate[N, W]) bool {
+	if c.p.Cost == o.p.Cost {
+		return len(c.p.Nodes) < len(o.p.Nodes)
+	}
+	return c.p.Cost < o.p.Cost
+}
+
+// filtered is a Graph with some nodes and edges removed.
+type filtered[N comparable, W Weight] struct {
+	g     Graph[N, W]
+	nodes map[N]bool
+	edges map[[2]N]bool
+}
+
+func (f *filtered[N, W]) Neighbors(n N, yield func(to N, w W) bool) {
+	if f.nodes[n] {
+		return
+	}
+	f.g.Neighbors(n, func(to N, w W) bool {
+		if f.nodes[to] || f.edges[[2]N{n, to}] {
+			return true
+		}
+		return yield(to, w)
+	})
+}
+
+// edgeWeight returns the weight of the lightest edge from u to v.
+func edgeWeight[N comparable, W Weight](g Graph[N, W], u, v N) W {
+	var (
+		found bool
+		min   W
+	)
+	g.Neighbors(u, func(to N, w W) bool {
+		if to == v && (!found || w < min) {
+			found, min = true, w
+		}
+		return true
+	})
+	return min
+}
+
+func containsPath[N comparable, W Weight](paths []Path[N, W], p Path[N, W]) bool {
+	for _, o := range paths {
+		if slices.Equal(o.Nodes, p.Nodes) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsCandidate[N comparable, W Weight](cs []candidate[N, W], p Path[N, W]) bool {
+	for _, c := range cs {
+		if slices.Equal(c.p.Nodes, p.Nodes) {
+			return true
+		}
+	}
+	return false
+}
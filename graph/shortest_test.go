@@ -0,0 +1,208 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package graph
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+// adj is a Graph stored as adjacency maps.
+type adj map[int]map[int]int
+
+func (g adj) Neighbors(n int, yield func(to int, w int) bool) {
+	for v, w := range g[n] {
+		if !yield(v, w) {
+			return
+		}
+	}
+}
+
+func (g adj) add(u, v, w int) {
+	if g[u] == nil {
+		g[u] = make(map[int]int)
+	}
+	g[u][v] = w
+}
+
+func (g adj) reverse() adj {
+	r := make(adj)
+	for u, es := range g {
+		for v, w := range es {
+			r.add(v, u, w)
+		}
+	}
+	return r
+}
+
+// randomGraph returns a directed graph on n nodes in which each edge is present with probability p and
+// weights are in [0, 5], so zero-weight edges are common.
+func randomGraph(r *rand.Rand, n int, p float64) adj {
+	g := make(adj)
+	for u := range n {
+		for v := range n {
+			if u != v && r.Float64() < p {
+				g.add(u, v, r.Intn(6))
+			}
+		}
+	}
+	return g
+}
+
+// allPaths returns the costs of every loopless path from src to dst, in increasing order.
+func allPaths(g adj, src, dst int) []int {
+	var (
+		costs   []int
+		visited = map[int]bool{src: true}
+		walk    func(u, cost int)
+	)
+	walk = func(u, cost int) {
+		if u == dst {
+			costs = append(costs, cost)
+			return
+		}
+		for v, w := range g[u] {
+			if !visited[v] {
+				visited[v] = true
+				walk(v, cost+w)
+				visited[v] = false
+			}
+		}
+	}
+	walk(src, 0)
+	slices.Sort(costs)
+	return costs
+}
+
+// checkPath checks that p runs from src to dst along edges of g with the cost it claims.
+func checkPath(t *testing.T, name string, g adj, p Path[int, int], src, dst int) {
+	t.Helper()
+	if len(p.Nodes) == 0 || p.Nodes[0] != src || p.Nodes[len(p.Nodes)-1] != dst {
+		t.Fatalf("%s: path %v does not run from %d to %d", name, p.Nodes, src, dst)
+	}
+	cost := 0
+	for i := 1; i < len(p.Nodes); i++ {
+		w, ok := g[p.Nodes[i-1]][p.Nodes[i]]
+		if !ok {
+			t.Fatalf("%s: path %v uses missing edge %d->%d", name, p.Nodes, p.Nodes[i-1], p.Nodes[i])
+		}
+		cost += w
+	}
+	if cost != p.Cost {
+		t.Fatalf("%s: path %v has cost %d, claims %d", name, p.Nodes, cost, p.Cost)
+	}
+}
+
+func hasLoop(nodes []int) bool {
+	seen := make(map[int]bool)
+	for _, n := range nodes {
+		if seen[n] {
+			return true
+		}
+		seen[n] = true
+	}
+	return false
+}
+
+func TestShortestPathsExhaustive(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for iter := range 300 {
+		n := 2 + r.Intn(6)
+		g := randomGraph(r, n, 0.1+0.5*r.Float64())
+		rev := g.reverse()
+		for src := range n {
+			for dst := range n {
+				costs := allPaths(g, src, dst)
+
+				// dist holds exact distances to dst, which make a consistent heuristic. Nodes that cannot
+				// reach dst get a large estimate, which stays consistent since their successors cannot either.
+				dist, _ := Dijkstra[int, int](rev, dst)
+				h := func(n int) int {
+					if d, ok := dist[n]; ok {
+						return d
+					}
+					return 1000
+				}
+
+				for _, tc := range []struct {
+					name string
+					f    func() (Path[int, int], bool)
+				}{
+					{"ShortestPath", func() (Path[int, int], bool) { return ShortestPath[int, int](g, src, dst) }},
+					{"AStar", func() (Path[int, int], bool) { return AStar[int, int](g, src, dst, h) }},
+					{"BidirectionalDijkstra", func() (Path[int, int], bool) {
+						return BidirectionalDijkstra[int, int](g, rev, src, dst)
+					}},
+				} {
+					p, ok := tc.f()
+					if ok != (len(costs) > 0) {
+						t.Fatalf("graph %d, %d->%d: %s reported %t, want %t", iter, src, dst, tc.name, ok, len(costs) > 0)
+					}
+					if !ok {
+						continue
+					}
+					checkPath(t, tc.name, g, p, src, dst)
+					if p.Cost != costs[0] {
+						t.Fatalf("graph %d, %d->%d: %s cost %d, want %d", iter, src, dst, tc.name, p.Cost, costs[0])
+					}
+				}
+
+				const k = 5
+				paths := KShortestPaths[int, int](g, src, dst, k)
+				want := costs[:min(k, len(costs))]
+				var got []int
+				for i, p := range paths {
+					checkPath(t, "KShortestPaths", g, p, src, dst)
+					if hasLoop(p.Nodes) {
+						t.Fatalf("graph %d, %d->%d: path %v has a loop", iter, src, dst, p.Nodes)
+					}
+					for _, q := range paths[:i] {
+						if slices.Equal(p.Nodes, q.Nodes) {
+							t.Fatalf("graph %d, %d->%d: path %v returned twice", iter, src, dst, p.Nodes)
+						}
+					}
+					got = append(got, p.Cost)
+				}
+				if !slices.Equal(got, want) {
+					t.Fatalf("graph %d, %d->%d: KShortestPaths costs %v, want %v", iter, src, dst, got, want)
+				}
+			}
+		}
+	}
+}
+
+func TestShortestPathSrcIsDst(t *testing.T) {
+	g := adj{0: {1: 2}, 1: {0: 0}}
+	for name, f := range map[string]func() (Path[int, int], bool){
+		"ShortestPath": func() (Path[int, int], bool) { return ShortestPath[int, int](g, 0, 0) },
+		"BidirectionalDijkstra": func() (Path[int, int], bool) {
+			return BidirectionalDijkstra[int, int](g, g.reverse(), 0, 0)
+		},
+	} {
+		if p, ok := f(); !ok || !slices.Equal(p.Nodes, []int{0}) || p.Cost != 0 {
+			t.Errorf("%s = %v, %t, want [0] with cost 0", name, p, ok)
+		}
+	}
+	if paths := KShortestPaths[int, int](g, 0, 0, 3); len(paths) != 1 || !slices.Equal(paths[0].Nodes, []int{0}) {
+		t.Errorf("KShortestPaths = %v, want only [0]", paths)
+	}
+}
+
+func TestShortestPathUnreachable(t *testing.T) {
+	g := adj{0: {1: 1}, 2: {0: 1}}
+	if _, ok := ShortestPath[int, int](g, 0, 2); ok {
+		t.Error("ShortestPath reported true")
+	}
+	if _, ok := BidirectionalDijkstra[int, int](g, g.reverse(), 0, 2); ok {
+		t.Error("BidirectionalDijkstra reported true")
+	}
+	if _, ok := ShortestPath[int, int](g, 0, 7); ok {
+		t.Error("ShortestPath to a missing node reported true")
+	}
+	if paths := KShortestPaths[int, int](g, 0, 2, 3); paths != nil {
+		t.Errorf("KShortestPaths = %v, want nil", paths)
+	}
+}
@@ -2,28 +2,61 @@
 // Use of this source code is governed by a BSD-style
 // license that can be found in the LICENSE file.
 
-// Package heap implements a generic heap using the standard library's container/heap.
+// Package heap implements a generic heap compatible with the standard library's container/heap.
+//
+// Heap implements container/heap.Interface, but its own methods sift elements directly with generic code, so
+// they avoid the interface dispatch and boxing of the container/heap functions.
 package heap
 
-import "container/heap"
-
 // A Comparable type can be compared with a method to other values of the same type.
 type Comparable[T any] interface {
 	// Less reports whether the receiver value must sort before the argument value.
 	Less(v T) bool
 }
 
+// An Indexed element is told its index whenever a Heap moves it. Elements removed from the Heap are given
+// index -1. This allows elements, typically pointers, to record their position for later calls to Fix or
+// RemoveElement.
+type Indexed interface {
+	SetIndex(i int)
+}
+
+// isIndexed reports whether T implements Indexed.
+func isIndexed[T any]() bool {
+	var zero T
+	_, ok := any(zero).(Indexed)
+	return ok
+}
+
 // A Heap is a min-heap implemented as a slice with generic methods.
 type Heap[T Comparable[T]] []T
 
 // Init establishes the heap invariants required by the other routines in this package.
 func (h *Heap[T]) Init() {
-	heap.Init(h)
+	h.init(h.ops())
+}
+
+func (h *Heap[T]) init(o siftOps[T]) {
+	n := h.Len()
+	if o.setIndex != nil {
+		for i, e := range *h {
+			o.setIndex(e, i)
+		}
+	}
+	for i := n/2 - 1; i >= 0; i-- {
+		siftDown(*h, i, n, o)
+	}
 }
 
 // Fix re-establishes the heap ordering after the element at index i has changed its value.
 func (h *Heap[T]) Fix(i int) {
-	heap.Fix(h, i)
+	h.fix(i, h.ops())
+}
+
+func (h *Heap[T]) fix(i int, o siftOps[T]) {
+	if !siftDown(*h, i, h.Len(), o) {
+		siftUp(*h, i, o)
+	}
 }
 
 // Len implements container/heap.Interface.Len and sort.Interface.Len.
@@ -47,23 +80,43 @@ func (h *Heap[T]) Swap(i int, j int) {
 		return
 	}
 	(*h)[i], (*h)[j] = (*h)[j], (*h)[i]
+	if isIndexed[T]() {
+		h.setIndexes(i, j)
+	}
 }
 
 // PushElement adds an element to the heap.
 func (h *Heap[T]) PushElement(e T) {
+	h.push(e, h.ops())
+}
+
+func (h *Heap[T]) push(e T, o siftOps[T]) {
 	*h = append(*h, e)
-	heap.Fix(h, len(*h)-1)
+	if o.setIndex != nil {
+		o.setIndex(e, len(*h)-1)
+	}
+	siftUp(*h, len(*h)-1, o)
 }
 
 // MustPopElement removes and returns the min element in the heap. It panics if no elements are in the heap.
 func (h *Heap[T]) MustPopElement() T {
+	return h.popMin(h.ops())
+}
+
+func (h *Heap[T]) popMin(o siftOps[T]) T {
 	e := (*h)[0]
 	i := h.Len() - 1
 	(*h)[0] = (*h)[i]
 	var zero T
 	(*h)[i] = zero
 	*h = (*h)[:i]
-	heap.Fix(h, 0)
+	if o.setIndex != nil {
+		o.setIndex(e, -1)
+		if i > 0 {
+			o.setIndex((*h)[0], 0)
+		}
+	}
+	siftDown(*h, 0, i, o)
 	return e
 }
 
@@ -104,13 +157,77 @@ func (h *Heap[T]) PeekElement() (T, bool) {
 	return (*h)[0], true
 }
 
+// PushPop adds e to the heap, then removes and returns the min element. If e would be the min element, it
+// is returned immediately without modifying the heap. It is more efficient than PushElement followed by
+// MustPopElement.
+func (h *Heap[T]) PushPop(e T) T {
+	if h.Len() == 0 || !(*h)[0].Less(e) {
+		return e
+	}
+	return h.replaceMin(e, h.ops())
+}
+
+// Replace removes and returns the min element in the heap, then adds e. It is more efficient than
+// MustPopElement followed by PushElement. It panics if no elements are in the heap.
+func (h *Heap[T]) Replace(e T) T {
+	return h.replaceMin(e, h.ops())
+}
+
+func (h *Heap[T]) replaceMin(e T, o siftOps[T]) T {
+	old := (*h)[0]
+	(*h)[0] = e
+	if o.setIndex != nil {
+		o.setIndex(old, -1)
+		o.setIndex(e, 0)
+	}
+	siftDown(*h, 0, h.Len(), o)
+	return old
+}
+
 // RemoveElement removes and returns the element at index i from the heap.
 func (h *Heap[T]) RemoveElement(i int) T {
+	return h.remove(i, h.ops())
+}
+
+func (h *Heap[T]) remove(i int, o siftOps[T]) T {
 	last := h.Len() - 1
-	h.Swap(i, last)
+	if i != last {
+		(*h)[i], (*h)[last] = (*h)[last], (*h)[i]
+		if o.swapped != nil {
+			o.swapped(i, last)
+		}
+	}
 	e := (*h)[last]
+	var zero T
+	(*h)[last] = zero
 	*h = (*h)[:last]
-	h.Fix(i)
+	if o.setIndex != nil {
+		o.setIndex(e, -1)
+	}
+	if i != last {
+		h.fix(i, o)
+	}
 
 	return e
 }
+
+// setIndex tells e, which must be Indexed, that it is at index i.
+func setIndex[T any](e T, i int) {
+	any(e).(Indexed).SetIndex(i)
+}
+
+// setIndexes tells the Indexed elements at indexes i and j where they are.
+func (h *Heap[T]) setIndexes(i, j int) {
+	setIndex((*h)[i], i)
+	setIndex((*h)[j], j)
+}
+
+// ops returns the siftOps that order h, notifying Indexed elements when they move.
+func (h *Heap[T]) ops() siftOps[T] {
+	o := siftOps[T]{less: T.Less}
+	if isIndexed[T]() {
+		o.swapped = h.setIndexes
+		o.setIndex = setIndex[T]
+	}
+	return o
+}
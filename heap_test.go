@@ -0,0 +1,421 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package heap
+
+import (
+	"fmt"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+type intElem int
+
+func (a intElem) Less(b intElem) bool {
+	return a < b
+}
+
+type ptrElem struct {
+	p     int
+	index int
+}
+
+func (a *ptrElem) Less(b *ptrElem) bool {
+	return a.p < b.p
+}
+
+func (a *ptrElem) SetIndex(i int) {
+	a.index = i
+}
+
+func checkHeap[T Comparable[T]](t *testing.T, h Heap[T]) {
+	t.Helper()
+	for i := 1; i < len(h); i++ {
+		if h[i].Less(h[(i-1)/2]) {
+			t.Fatalf("element %d is less than its parent", i)
+		}
+	}
+}
+
+func checkIndexes(t *testing.T, h Heap[*ptrElem]) {
+	t.Helper()
+	for i, e := range h {
+		if e.index != i {
+			t.Fatalf("element at %d has index %d", i, e.index)
+		}
+	}
+}
+
+func TestHeapOrder(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	var (
+		h    Heap[intElem]
+		want []intElem
+	)
+	for range 1000 {
+		v := intElem(r.Intn(100))
+		h.PushElement(v)
+		want = append(want, v)
+	}
+	checkHeap(t, h)
+	slices.Sort(want)
+	for i, w := range want {
+		if got := h.MustPopElement(); got != w {
+			t.Fatalf("pop %d = %d, want %d", i, got, w)
+		}
+	}
+	if _, ok := h.PopElement(); ok {
+		t.Error("PopElement on empty heap reported true")
+	}
+}
+
+func TestHeapIndexed(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	var (
+		h   Heap[*ptrElem]
+		all []*ptrElem
+	)
+	for range 200 {
+		e := &ptrElem{p: r.Intn(50)}
+		all = append(all, e)
+		h.PushElement(e)
+	}
+	checkIndexes(t, h)
+
+	for _, e := range all {
+		if r.Intn(3) == 0 {
+			h.RemoveElement(e.index)
+			if e.index != -1 {
+				t.Fatalf("removed element has index %d, want -1", e.index)
+			}
+		}
+	}
+	checkIndexes(t, h)
+	checkHeap(t, h)
+
+	for _, e := range all {
+		if e.index >= 0 {
+			e.p = r.Intn(50)
+			h.Fix(e.index)
+		}
+	}
+	checkIndexes(t, h)
+	checkHeap(t, h)
+
+	for h.Len() > 0 {
+		if e := h.MustPopElement(); e.index != -1 {
+			t.Fatalf("popped element has index %d, want -1", e.index)
+		}
+		checkIndexes(t, h)
+	}
+}
+
+func TestPopBatch(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	var (
+		h    Heap[*ptrElem]
+		want []int
+	)
+	for range 100 {
+		e := &ptrElem{p: r.Intn(50)}
+		h.PushElement(e)
+		want = append(want, e.p)
+	}
+	slices.Sort(want)
+
+	var got []int
+	for _, e := range h.PopN(30) {
+		got = append(got, e.p)
+	}
+	for _, e := range h.PopWhile(func(e *ptrElem) bool { return e.p < want[60] }) {
+		got = append(got, e.p)
+	}
+	for _, e := range h.PopAppend(make([]*ptrElem, 0, 1), 1000) {
+		if e.index != -1 {
+			t.Fatalf("popped element has index %d, want -1", e.index)
+		}
+		got = append(got, e.p)
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("popped %v, want %v", got, want)
+	}
+	if h.Len() != 0 || len(h.PopN(1)) != 0 {
+		t.Errorf("heap not empty after popping everything")
+	}
+}
+
+func TestRemoveLast(t *testing.T) {
+	h := Heap[intElem]{1, 2, 3}
+	if got := h.RemoveElement(2); got != 3 {
+		t.Errorf("RemoveElement(2) = %d, want 3", got)
+	}
+	if got := h.RemoveElement(1); got != 2 {
+		t.Errorf("RemoveElement(1) = %d, want 2", got)
+	}
+	if got := h.RemoveElement(0); got != 1 {
+		t.Errorf("RemoveElement(0) = %d, want 1", got)
+	}
+	if h.Len() != 0 {
+		t.Errorf("Len() = %d, want 0", h.Len())
+	}
+}
+
+func BenchmarkPushPopInt(b *testing.B) {
+	r := rand.New(rand.NewSource(1))
+	var h Heap[intElem]
+	for range 10000 {
+		h.PushElement(intElem(r.Intn(1 << 20)))
+	}
+	b.ResetTimer()
+	for range b.N {
+		h.PushElement(intElem(r.Intn(1 << 20)))
+		h.MustPopElement()
+	}
+}
+
+func BenchmarkPopN(b *testing.B) {
+	const size = 100000
+	r := rand.New(rand.NewSource(1))
+	var h Heap[intElem]
+	for range size {
+		h.PushElement(intElem(r.Intn(1 << 20)))
+	}
+	for _, n := range []int{size / 100, size / 4, size / 2, size} {
+		b.Run(fmt.Sprintf("n=%d", n), func(b *testing.B) {
+			dst := make([]intElem, 0, n)
+			for range b.N {
+				b.StopTimer()
+				c := slices.Clone(h)
+				b.StartTimer()
+				dst = c.PopAppend(dst[:0], n)
+			}
+		})
+	}
+}
+
+type benchPtr struct {
+	p int
+}
+
+func (a *benchPtr) Less(b *benchPtr) bool {
+	return a.p < b.p
+}
+
+func BenchmarkPushPopPtr(b *testing.B) {
+	r := rand.New(rand.NewSource(1))
+	ps := make([]*benchPtr, 1<<16)
+	for i := range ps {
+		ps[i] = &benchPtr{r.Intn(1 << 20)}
+	}
+	var h Heap[*benchPtr]
+	for _, p := range ps[:10000] {
+		h.PushElement(p)
+	}
+	b.ResetTimer()
+	for i := range b.N {
+		h.PushElement(ps[i&(len(ps)-1)])
+		h.MustPopElement()
+	}
+}
+
+// newPtrHeap returns a heap of n elements with random priorities below max, and the elements in push order.
+func newPtrHeap(r *rand.Rand, n, max int) (Heap[*ptrElem], []*ptrElem) {
+	var (
+		h   Heap[*ptrElem]
+		all []*ptrElem
+	)
+	for range n {
+		e := &ptrElem{p: r.Intn(max)}
+		all = append(all, e)
+		h.PushElement(e)
+	}
+	return h, all
+}
+
+func TestPushPopReplace(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	h, _ := newPtrHeap(r, 100, 100)
+	for range 1000 {
+		e := &ptrElem{p: r.Intn(100), index: -1}
+		min := h[0]
+		if r.Intn(2) == 0 {
+			got := h.PushPop(e)
+			want := min
+			if e.p <= min.p {
+				want = e
+			}
+			if got != want {
+				t.Fatalf("PushPop(%d) = %d, want %d", e.p, got.p, want.p)
+			}
+			if got.index != -1 {
+				t.Fatalf("PushPop returned an element with index %d, want -1", got.index)
+			}
+		} else {
+			if got := h.Replace(e); got != min || got.index != -1 {
+				t.Fatalf("Replace(%d) = %d with index %d, want %d with index -1", e.p, got.p, got.index, min.p)
+			}
+		}
+		if h.Len() != 100 {
+			t.Fatalf("Len() = %d, want 100", h.Len())
+		}
+		checkHeap(t, h)
+		checkIndexes(t, h)
+	}
+
+	var empty Heap[*ptrElem]
+	e := &ptrElem{p: 1, index: -1}
+	if got := empty.PushPop(e); got != e || empty.Len() != 0 {
+		t.Errorf("PushPop on empty heap = %v, Len() = %d, want e and 0", got, empty.Len())
+	}
+}
+
+func TestRemoveFunc(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for trial := range 500 {
+		n := 1 + r.Intn(300)
+		h, all := newPtrHeap(r, n, 20)
+		// Small fractions take the per-element path; large ones rebuild the heap.
+		frac := []float64{0.01, 0.03, 0.1, 0.5, 1}[trial%5]
+		doomed := make(map[*ptrElem]bool)
+		for _, e := range all {
+			if r.Float64() < frac {
+				doomed[e] = true
+			}
+		}
+		calls := make(map[*ptrElem]int)
+		removed := h.RemoveFunc(func(e *ptrElem) bool {
+			calls[e]++
+			return doomed[e]
+		})
+
+		if len(removed) != len(doomed) {
+			t.Fatalf("trial %d: removed %d elements, want %d", trial, len(removed), len(doomed))
+		}
+		for _, e := range removed {
+			if !doomed[e] || e.index != -1 {
+				t.Fatalf("trial %d: removed %+v, doomed %t", trial, e, doomed[e])
+			}
+		}
+		for _, e := range all {
+			if calls[e] != 1 {
+				t.Fatalf("trial %d: pred called %d times for an element", trial, calls[e])
+			}
+		}
+		if h.Len() != n-len(doomed) {
+			t.Fatalf("trial %d: Len() = %d, want %d", trial, h.Len(), n-len(doomed))
+		}
+		for _, e := range h {
+			if doomed[e] {
+				t.Fatalf("trial %d: doomed element %+v is still in the heap", trial, e)
+			}
+		}
+		checkHeap(t, h)
+		checkIndexes(t, h)
+	}
+}
+
+func TestRetainAndUpdateFunc(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	h, _ := newPtrHeap(r, 200, 100)
+	h.Retain(func(e *ptrElem) bool { return e.p%3 != 0 })
+	for _, e := range h {
+		if e.p%3 == 0 {
+			t.Fatalf("Retain kept %d", e.p)
+		}
+	}
+	checkHeap(t, h)
+	checkIndexes(t, h)
+
+	for range 500 {
+		i := r.Intn(h.Len())
+		p := r.Intn(100)
+		h.UpdateFunc(i, func(e **ptrElem) { (*e).p = p })
+		checkHeap(t, h)
+		checkIndexes(t, h)
+	}
+
+	// UpdateFunc may replace the element itself, whether or not the replacement moves.
+	last := h.Len() - 1
+	stay := &ptrElem{p: h[last].p, index: -1}
+	h.UpdateFunc(last, func(old **ptrElem) { *old = stay })
+	if h[last] != stay || stay.index != last {
+		t.Errorf("replacement is at %d with index %d, want %d", slices.Index(h, stay), stay.index, last)
+	}
+	checkIndexes(t, h)
+
+	e := &ptrElem{p: -1}
+	h.UpdateFunc(h.Len()-1, func(old **ptrElem) { *old = e })
+	if h[0] != e || e.index != 0 {
+		t.Errorf("replaced element is at %d with index %d, want the root", slices.Index(h, e), e.index)
+	}
+}
+
+func TestIndex(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for range 100 {
+		h, all := newPtrHeap(r, r.Intn(200), 30)
+		for _, e := range all {
+			if i := Index(&h, e); i != e.index {
+				t.Fatalf("Index(%+v) = %d, want %d", e, i, e.index)
+			}
+		}
+		// Elements with the same priority as ones in the heap, but not in it, are not found.
+		for p := -1; p <= 30; p++ {
+			if i := Index(&h, &ptrElem{p: p}); i != -1 {
+				t.Fatalf("Index of an absent element with priority %d = %d", p, i)
+			}
+		}
+
+		for _, e := range all {
+			if r.Intn(2) == 0 {
+				continue
+			}
+			if !Contains(&h, e) || !RemoveValue(&h, e) {
+				t.Fatalf("element %+v not found", e)
+			}
+			if e.index != -1 || Contains(&h, e) || RemoveValue(&h, e) {
+				t.Fatalf("element %+v found after removal", e)
+			}
+			checkHeap(t, h)
+			checkIndexes(t, h)
+		}
+	}
+}
+
+func TestIndexValues(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	var (
+		h     Heap[intElem]
+		count = make(map[intElem]int)
+	)
+	for range 500 {
+		v := intElem(r.Intn(100))
+		h.PushElement(v)
+		count[v]++
+	}
+	for v := intElem(-1); v <= 100; v++ {
+		i := Index(&h, v)
+		if (i >= 0) != (count[v] > 0) || i >= 0 && h[i] != v {
+			t.Fatalf("Index(%d) = %d with %d copies", v, i, count[v])
+		}
+		if i := h.IndexFunc(func(e intElem) bool { return e == v }); (i >= 0) != (count[v] > 0) {
+			t.Fatalf("IndexFunc for %d = %d with %d copies", v, i, count[v])
+		}
+	}
+	for v, n := range count {
+		for range n {
+			if !RemoveValue(&h, v) {
+				t.Fatalf("RemoveValue(%d) reported false", v)
+			}
+		}
+		if Contains(&h, v) {
+			t.Fatalf("Contains(%d) after removing every copy", v)
+		}
+		checkHeap(t, h)
+	}
+	if h.Len() != 0 {
+		t.Errorf("Len() = %d, want 0", h.Len())
+	}
+}
@@ -0,0 +1,142 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package huffman constructs Huffman codes from symbol frequencies.
+package huffman
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/iangudger/heap"
+)
+
+// A Code is a prefix code for a symbol.
+type Code struct {
+	// Bits holds the code in its low Len bits, with the first bit of the code in the most significant
+	// position.
+	Bits uint64
+
+	// Len is the length of the code in bits.
+	Len int
+}
+
+// node is a Huffman tree node. Leaves have no children.
+type node[S cmp.Ordered] struct {
+	sym         S
+	freq        int
+	left, right *node[S]
+
+	// seq breaks ties between equal frequencies so that the tree does not depend on map iteration order.
+	seq int
+}
+
+func (n *node[S]) Less(o *node[S]) bool {
+	if n.freq == o.freq {
+		return n.seq < o.seq
+	}
+	return n.freq < o.freq
+}
+
+// tree builds a Huffman tree for freqs. It returns nil if freqs is empty.
+func tree[S cmp.Ordered](freqs map[S]int) *node[S] {
+	syms := make([]S, 0, len(freqs))
+	for s := range freqs {
+		syms = append(syms, s)
+	}
+	slices.Sort(syms)
+
+	var h heap.Heap[*node[S]]
+	for i, s := range syms {
+		h.PushElement(&node[S]{sym: s, freq: freqs[s], seq: i})
+	}
+	seq := len(syms)
+	for h.Len() > 1 {
+		a, b := h.MustPopElement(), h.MustPopElement()
+		h.PushElement(&node[S]{freq: a.freq + b.freq, left: a, right: b, seq: seq})
+		seq++
+	}
+	root, _ := h.PopElement()
+	return root
+}
+
+// walk calls f for each leaf below n with its code.
+func walk[S cmp.Ordered](n *node[S], c Code, f func(S, Code)) {
+	if n.left == nil {
+		f(n.sym, c)
+		return
+	}
+	if c.Len == 64 {
+		panic("huffman: code longer than 64 bits")
+	}
+	walk(n.left, Code{c.Bits << 1, c.Len + 1}, f)
+	walk(n.right, Code{c.Bits<<1 | 1, c.Len + 1}, f)
+}
+
+// Lengths returns the Huffman code length of each symbol in freqs. A lone symbol is given length 1.
+func Lengths[S cmp.Ordered](freqs map[S]int) map[S]int {
+	lengths := make(map[S]int, len(freqs))
+	for s, c := range Build(freqs) {
+		lengths[s] = c.Len
+	}
+	return lengths
+}
+
+// Build returns a Huffman code for each symbol in freqs, read from the Huffman tree. A lone symbol is given
+// the one-bit code 0. It panics if a code would be longer than 64 bits.
+func Build[S cmp.Ordered](freqs map[S]int) map[S]Code {
+	codes := make(map[S]Code, len(freqs))
+	root := tree(freqs)
+	switch {
+	case root == nil:
+	case root.left == nil:
+		codes[root.sym] = Code{Len: 1}
+	default:
+		walk(root, Code{}, func(s S, c Code) {
+			codes[s] = c
+		})
+	}
+	return codes
+}
+
+// Canonical returns the canonical Huffman code for the given code lengths. Codes are assigned in order of
+// increasing length, then increasing symbol, so only the lengths need to be transmitted for a decoder to
+// reconstruct them. Symbols with length zero are omitted. It panics if a length is greater than 64.
+func Canonical[S cmp.Ordered](lengths map[S]int) map[S]Code {
+	syms := make([]S, 0, len(lengths))
+	for s, l := range lengths {
+		if l > 64 {
+			panic("huffman: code longer than 64 bits")
+		}
+		if l > 0 {
+			syms = append(syms, s)
+		}
+	}
+	slices.SortFunc(syms, func(a, b S) int {
+		if c := cmp.Compare(lengths[a], lengths[b]); c != 0 {
+			return c
+		}
+		return cmp.Compare(a, b)
+	})
+
+	codes := make(map[S]Code, len(syms))
+	var (
+		bits uint64
+		prev int
+	)
+	for i, s := range syms {
+		l := lengths[s]
+		if i > 0 {
+			bits = (bits + 1) << (l - prev)
+		}
+		codes[s] = Code{Bits: bits, Len: l}
+		prev = l
+	}
+	return codes
+}
+
+// BuildCanonical returns the canonical Huffman code for freqs.
+func BuildCanonical[S cmp.Ordered](freqs map[S]int) map[S]Code {
+	return Canonical(Lengths(freqs))
+}
@@ -0,0 +1,59 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package huffman
+
+import (
+	"maps"
+	"testing"
+)
+
+// clrs is the frequency table from Cormen et al., "Introduction to Algorithms", Section 16.3.
+var clrs = map[byte]int{'a': 45, 'b': 13, 'c': 12, 'd': 16, 'e': 9, 'f': 5}
+
+func TestBuild(t *testing.T) {
+	want := map[byte]Code{
+		'a': {0b0, 1},
+		'b': {0b101, 3},
+		'c': {0b100, 3},
+		'd': {0b111, 3},
+		'e': {0b1101, 4},
+		'f': {0b1100, 4},
+	}
+	got := Build(clrs)
+	if !maps.Equal(got, want) {
+		t.Errorf("Build() = %v, want %v", got, want)
+	}
+	cost := 0
+	for s, c := range got {
+		cost += clrs[s] * c.Len
+	}
+	if cost != 224 {
+		t.Errorf("encoded length = %d bits, want 224", cost)
+	}
+}
+
+func TestBuildCanonical(t *testing.T) {
+	want := map[byte]Code{
+		'a': {0b0, 1},
+		'b': {0b100, 3},
+		'c': {0b101, 3},
+		'd': {0b110, 3},
+		'e': {0b1110, 4},
+		'f': {0b1111, 4},
+	}
+	if got := BuildCanonical(clrs); !maps.Equal(got, want) {
+		t.Errorf("BuildCanonical() = %v, want %v", got, want)
+	}
+}
+
+func TestBuildSmall(t *testing.T) {
+	if got := Build(map[byte]int{}); len(got) != 0 {
+		t.Errorf("Build(empty) = %v, want empty", got)
+	}
+	want := map[byte]Code{'x': {0, 1}}
+	if got := Build(map[byte]int{'x': 7}); !maps.Equal(got, want) {
+		t.Errorf("Build(lone symbol) = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,193 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package heap
+
+import (
+	"expvar"
+	"sync/atomic"
+)
+
+// Stats is a snapshot of the work done by an Instrumented heap.
+type Stats struct {
+	// Pushes and Pops count elements added to and removed from the heap. Pops includes RemoveElement.
+	Pushes, Pops uint64
+
+	// Compares and Swaps count calls to Less and element exchanges made while sifting.
+	Compares, Swaps uint64
+
+	// PeakLen is the largest number of elements the heap has held.
+	PeakLen int
+
+	// Grows counts the times the heap's backing array was reallocated to grow its capacity.
+	Grows uint64
+}
+
+// An Instrumented is a min-heap that records statistics about the work it does.
+//
+// Its heap operations are not safe for concurrent use, but Stats and the expvar.Var returned by Var may be
+// called concurrently with them.
+type Instrumented[T Comparable[T]] struct {
+	h Heap[T]
+
+	// OnPush, if not nil, is called with each element after it is added to the heap.
+	OnPush func(T)
+
+	// OnPop, if not nil, is called with each element after it is removed from the heap, including by
+	// RemoveElement.
+	OnPop func(T)
+
+	pushes, pops    atomic.Uint64
+	compares, swaps atomic.Uint64
+	grows           atomic.Uint64
+	peak            atomic.Int64
+}
+
+// Stats returns a snapshot of the heap's statistics.
+func (h *Instrumented[T]) Stats() Stats {
+	return Stats{
+		Pushes:   h.pushes.Load(),
+		Pops:     h.pops.Load(),
+		Compares: h.compares.Load(),
+		Swaps:    h.swaps.Load(),
+		PeakLen:  int(h.peak.Load()),
+		Grows:    h.grows.Load(),
+	}
+}
+
+// Var returns an expvar.Var that reports the heap's Stats as JSON. It can be registered with expvar.Publish.
+func (h *Instrumented[T]) Var() expvar.Var {
+	return expvar.Func(func() any {
+		return h.Stats()
+	})
+}
+
+// Len returns the number of elements in the heap.
+func (h *Instrumented[T]) Len() int {
+	return h.h.Len()
+}
+
+// Init establishes the heap invariants required by the other methods.
+func (h *Instrumented[T]) Init() {
+	var c siftCounts
+	h.h.init(h.ops(&c))
+	h.record(c)
+}
+
+// Reset replaces the elements of the heap with s and re-establishes the heap invariants. The heap takes
+// ownership of s.
+func (h *Instrumented[T]) Reset(s []T) {
+	h.h = s
+	h.updatePeak()
+	h.Init()
+}
+
+// Fix re-establishes the heap ordering after the element at index i has changed its value.
+func (h *Instrumented[T]) Fix(i int) {
+	var c siftCounts
+	h.h.fix(i, h.ops(&c))
+	h.record(c)
+}
+
+// At returns the element at index i.
+func (h *Instrumented[T]) At(i int) T {
+	return h.h[i]
+}
+
+// PushElement adds an element to the heap.
+func (h *Instrumented[T]) PushElement(e T) {
+	var c siftCounts
+	oldCap := cap(h.h)
+	h.h.push(e, h.ops(&c))
+	if cap(h.h) != oldCap {
+		h.grows.Add(1)
+	}
+	h.pushes.Add(1)
+	h.record(c)
+	h.updatePeak()
+	if h.OnPush != nil {
+		h.OnPush(e)
+	}
+}
+
+// MustPopElement removes and returns the min element in the heap. It panics if no elements are in the heap.
+func (h *Instrumented[T]) MustPopElement() T {
+	var c siftCounts
+	e := h.h.popMin(h.ops(&c))
+	h.popped(e, c)
+	return e
+}
+
+// PopElement removes and returns the min element in the heap.
+func (h *Instrumented[T]) PopElement() (T, bool) {
+	if h.h.Len() == 0 {
+		var zero T
+		return zero, false
+	}
+	return h.MustPopElement(), true
+}
+
+// MustPeekElement returns the min element in the heap. It panics if no elements are in the heap.
+func (h *Instrumented[T]) MustPeekElement() T {
+	return h.h.MustPeekElement()
+}
+
+// PeekElement returns the min element in the heap.
+func (h *Instrumented[T]) PeekElement() (T, bool) {
+	return h.h.PeekElement()
+}
+
+// RemoveElement removes and returns the element at index i from the heap.
+func (h *Instrumented[T]) RemoveElement(i int) T {
+	var c siftCounts
+	e := h.h.remove(i, h.ops(&c))
+	h.popped(e, c)
+	return e
+}
+
+// siftCounts accumulates the work done by one heap operation.
+type siftCounts struct {
+	compares, swaps uint64
+}
+
+// ops returns siftOps that order the heap like Heap's own and count the work done into c.
+func (h *Instrumented[T]) ops(c *siftCounts) siftOps[T] {
+	base := h.h.ops()
+	return siftOps[T]{
+		less: func(a, b T) bool {
+			c.compares++
+			return a.Less(b)
+		},
+		swapped: func(i, j int) {
+			c.swaps++
+			if base.swapped != nil {
+				base.swapped(i, j)
+			}
+		},
+		setIndex: base.setIndex,
+	}
+}
+
+func (h *Instrumented[T]) popped(e T, c siftCounts) {
+	h.pops.Add(1)
+	h.record(c)
+	if h.OnPop != nil {
+		h.OnPop(e)
+	}
+}
+
+func (h *Instrumented[T]) record(c siftCounts) {
+	if c.compares != 0 {
+		h.compares.Add(c.compares)
+	}
+	if c.swaps != 0 {
+		h.swaps.Add(c.swaps)
+	}
+}
+
+func (h *Instrumented[T]) updatePeak() {
+	if n := int64(h.h.Len()); n > h.peak.Load() {
+		h.peak.Store(n)
+	}
+}
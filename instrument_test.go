@@ -0,0 +1,64 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package heap
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestInstrumented(t *testing.T) {
+	var (
+		h              Instrumented[*ptrElem]
+		pushed, popped int
+	)
+	h.OnPush = func(*ptrElem) { pushed++ }
+	h.OnPop = func(*ptrElem) { popped++ }
+	for i := range 100 {
+		h.PushElement(&ptrElem{p: 100 - i})
+	}
+	checkIndexes(t, h.h)
+	prev := 0
+	for range 60 {
+		e := h.MustPopElement()
+		if e.p < prev || e.index != -1 {
+			t.Fatalf("popped %+v after %d", e, prev)
+		}
+		prev = e.p
+	}
+	h.RemoveElement(h.Len() - 1)
+	checkIndexes(t, h.h)
+
+	s := h.Stats()
+	if s.Pushes != 100 || s.Pops != 61 || s.PeakLen != 100 {
+		t.Errorf("Stats() = %+v, want 100 pushes, 61 pops and peak length 100", s)
+	}
+	if s.Compares == 0 || s.Swaps == 0 || s.Grows == 0 {
+		t.Errorf("Stats() = %+v, want nonzero compares, swaps and grows", s)
+	}
+	if pushed != 100 || popped != 61 {
+		t.Errorf("hooks saw %d pushes and %d pops, want 100 and 61", pushed, popped)
+	}
+
+	var got Stats
+	if err := json.Unmarshal([]byte(h.Var().String()), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got != s {
+		t.Errorf("Var() = %+v, want %+v", got, s)
+	}
+}
+
+func TestInstrumentedDoesNotCountPlainHeap(t *testing.T) {
+	var h Instrumented[intElem]
+	h.PushElement(2)
+	h.PushElement(1)
+	before := h.Stats()
+	h.h.PushElement(0)
+	h.h.MustPopElement()
+	if after := h.Stats(); after != before {
+		t.Errorf("plain Heap operations changed Stats from %+v to %+v", before, after)
+	}
+}
@@ -0,0 +1,156 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package heap
+
+// DefaultMaxDeadFraction is the fraction of tombstoned elements above which a LazyHeap compacts itself, if
+// no other fraction is given.
+const DefaultMaxDeadFraction = 0.5
+
+// A LazyHeap is a min-heap whose elements can be deleted by key without knowing their index.
+//
+// Deleted elements are left in the heap as tombstones and skipped by PeekElement and PopElement. When
+// tombstones make up more than a configured fraction of the heap, it is compacted in O(n) time.
+type LazyHeap[K comparable, T Comparable[T]] struct {
+	key        func(T) K
+	maxDead    float64
+	h          Heap[lazyElement[K, T]]
+	keys       map[K]*lazyKey
+	tombstones int
+}
+
+type lazyElement[K comparable, T Comparable[T]] struct {
+	v   T
+	k   K
+	gen uint64
+}
+
+func (e lazyElement[K, T]) Less(o lazyElement[K, T]) bool {
+	return e.v.Less(o.v)
+}
+
+// lazyKey tracks the elements of a LazyHeap with the same key. MarkDeleted increments gen, which turns every
+// element pushed with an earlier gen into a tombstone.
+type lazyKey struct {
+	gen   uint64
+	live  int
+	total int
+}
+
+// NewLazyHeap returns an empty LazyHeap that identifies elements with key. The heap is compacted whenever
+// tombstones exceed maxDeadFraction of its elements; a maxDeadFraction of zero or less uses
+// DefaultMaxDeadFraction.
+func NewLazyHeap[K comparable, T Comparable[T]](key func(T) K, maxDeadFraction float64) *LazyHeap[K, T] {
+	if maxDeadFraction <= 0 {
+		maxDeadFraction = DefaultMaxDeadFraction
+	}
+	return &LazyHeap[K, T]{
+		key:     key,
+		maxDead: maxDeadFraction,
+		keys:    make(map[K]*lazyKey),
+	}
+}
+
+// Len returns the number of elements in the heap, not counting tombstones.
+func (h *LazyHeap[K, T]) Len() int {
+	return h.h.Len() - h.tombstones
+}
+
+// Tombstones returns the number of deleted elements that have not yet been removed from the heap.
+func (h *LazyHeap[K, T]) Tombstones() int {
+	return h.tombstones
+}
+
+// PushElement adds an element to the heap.
+func (h *LazyHeap[K, T]) PushElement(e T) {
+	k := h.key(e)
+	ks, ok := h.keys[k]
+	if !ok {
+		ks = &lazyKey{}
+		h.keys[k] = ks
+	}
+	ks.live++
+	ks.total++
+	h.h.PushElement(lazyElement[K, T]{v: e, k: k, gen: ks.gen})
+}
+
+// PopElement removes and returns the min element in the heap.
+func (h *LazyHeap[K, T]) PopElement() (T, bool) {
+	h.prune()
+	e, ok := h.h.PopElement()
+	if ok {
+		ks := h.keys[e.k]
+		ks.live--
+		h.release(e.k, ks)
+	}
+	return e.v, ok
+}
+
+// PeekElement returns the min element in the heap.
+func (h *LazyHeap[K, T]) PeekElement() (T, bool) {
+	h.prune()
+	e, ok := h.h.PeekElement()
+	return e.v, ok
+}
+
+// MarkDeleted deletes every element in the heap with key k and returns how many there were.
+func (h *LazyHeap[K, T]) MarkDeleted(k K) int {
+	ks, ok := h.keys[k]
+	if !ok || ks.live == 0 {
+		return 0
+	}
+	n := ks.live
+	ks.gen++
+	ks.live = 0
+	h.tombstones += n
+	if float64(h.tombstones) > h.maxDead*float64(h.h.Len()) {
+		h.Compact()
+	}
+	return n
+}
+
+// Compact removes all tombstones from the heap.
+func (h *LazyHeap[K, T]) Compact() {
+	if h.tombstones == 0 {
+		return
+	}
+	live := h.h[:0]
+	for _, e := range h.h {
+		if h.keys[e.k].gen == e.gen {
+			live = append(live, e)
+		}
+	}
+	clear(h.h[len(live):])
+	h.h = live
+	h.h.Init()
+	for k, ks := range h.keys {
+		ks.total = ks.live
+		if ks.total == 0 {
+			delete(h.keys, k)
+		}
+	}
+	h.tombstones = 0
+}
+
+// prune pops tombstones from the top of the heap.
+func (h *LazyHeap[K, T]) prune() {
+	for h.h.Len() > 0 {
+		e := h.h[0]
+		ks := h.keys[e.k]
+		if ks.gen == e.gen {
+			return
+		}
+		h.h.MustPopElement()
+		h.tombstones--
+		h.release(e.k, ks)
+	}
+}
+
+// release accounts for an element with key k leaving the heap.
+func (h *LazyHeap[K, T]) release(k K, ks *lazyKey) {
+	ks.total--
+	if ks.total == 0 {
+		delete(h.keys, k)
+	}
+}
@@ -0,0 +1,150 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package heap
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+type lazyItem struct {
+	k string
+	p int
+}
+
+func (a lazyItem) Less(b lazyItem) bool {
+	return a.p < b.p
+}
+
+func lazyItemKey(e lazyItem) string {
+	return e.k
+}
+
+func checkLazy(t *testing.T, h *LazyHeap[string, lazyItem], n, tombstones int) {
+	t.Helper()
+	if h.Len() != n || h.Tombstones() != tombstones {
+		t.Fatalf("Len() = %d, Tombstones() = %d, want %d and %d", h.Len(), h.Tombstones(), n, tombstones)
+	}
+}
+
+func TestLazyHeapMarkDeleted(t *testing.T) {
+	h := NewLazyHeap(lazyItemKey, 0.9)
+	h.PushElement(lazyItem{"a", 1})
+	h.PushElement(lazyItem{"b", 2})
+	h.PushElement(lazyItem{"a", 3})
+	h.PushElement(lazyItem{"c", 4})
+	if n := h.MarkDeleted("a"); n != 2 {
+		t.Fatalf("MarkDeleted(a) = %d, want 2", n)
+	}
+	if n := h.MarkDeleted("a"); n != 0 {
+		t.Errorf("second MarkDeleted(a) = %d, want 0", n)
+	}
+	if n := h.MarkDeleted("missing"); n != 0 {
+		t.Errorf("MarkDeleted(missing) = %d, want 0", n)
+	}
+	checkLazy(t, h, 2, 2)
+
+	if e, ok := h.PeekElement(); !ok || e.k != "b" {
+		t.Fatalf("PeekElement() = %v, %t, want b", e, ok)
+	}
+	checkLazy(t, h, 2, 1) // a/1 was pruned from the top
+	if e, _ := h.PopElement(); e.k != "b" {
+		t.Fatalf("PopElement() = %v, want b", e)
+	}
+	if e, _ := h.PopElement(); e.k != "c" {
+		t.Fatalf("PopElement() = %v, want c", e)
+	}
+	checkLazy(t, h, 0, 0)
+	if _, ok := h.PopElement(); ok {
+		t.Error("PopElement() on empty heap reported true")
+	}
+	if len(h.keys) != 0 {
+		t.Errorf("%d keys still tracked in an empty heap", len(h.keys))
+	}
+}
+
+func TestLazyHeapAutoCompact(t *testing.T) {
+	h := NewLazyHeap(lazyItemKey, 0)
+	for i, k := range []string{"a", "b", "c", "d"} {
+		h.PushElement(lazyItem{k, i})
+	}
+	h.MarkDeleted("a")
+	h.MarkDeleted("b")
+	checkLazy(t, h, 2, 2) // exactly half are tombstones, which does not exceed DefaultMaxDeadFraction
+	h.MarkDeleted("c")
+	checkLazy(t, h, 1, 0)
+	if n := h.h.Len(); n != 1 {
+		t.Errorf("heap holds %d elements after compacting, want 1", n)
+	}
+	if e, _ := h.PopElement(); e.k != "d" {
+		t.Errorf("PopElement() = %v, want d", e)
+	}
+}
+
+func TestLazyHeapRepush(t *testing.T) {
+	h := NewLazyHeap(lazyItemKey, 0.9)
+	h.PushElement(lazyItem{"a", 5})
+	h.PushElement(lazyItem{"b", 6})
+	h.MarkDeleted("a")
+	h.PushElement(lazyItem{"a", 7})
+	h.PushElement(lazyItem{"a", 1})
+	checkLazy(t, h, 3, 1)
+
+	var got []int
+	for {
+		e, ok := h.PopElement()
+		if !ok {
+			break
+		}
+		got = append(got, e.p)
+	}
+	if !slices.Equal(got, []int{1, 6, 7}) {
+		t.Errorf("popped %v, want [1 6 7]", got)
+	}
+	checkLazy(t, h, 0, 0)
+}
+
+func TestLazyHeapRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	keys := []string{"a", "b", "c", "d", "e"}
+	h := NewLazyHeap(lazyItemKey, 0.3)
+	var ref []lazyItem
+	for range 5000 {
+		switch op := r.Intn(10); {
+		case op < 5:
+			e := lazyItem{keys[r.Intn(len(keys))], r.Intn(100)}
+			h.PushElement(e)
+			ref = append(ref, e)
+		case op < 7:
+			k := keys[r.Intn(len(keys))]
+			want := len(ref)
+			ref = slices.DeleteFunc(ref, func(e lazyItem) bool { return e.k == k })
+			if n := h.MarkDeleted(k); n != want-len(ref) {
+				t.Fatalf("MarkDeleted(%s) = %d, want %d", k, n, want-len(ref))
+			}
+		case op < 9:
+			e, ok := h.PopElement()
+			if ok != (len(ref) > 0) {
+				t.Fatalf("PopElement() reported %t with %d elements", ok, len(ref))
+			}
+			if !ok {
+				continue
+			}
+			i := slices.IndexFunc(ref, func(x lazyItem) bool { return x == e })
+			if i < 0 || slices.ContainsFunc(ref, func(x lazyItem) bool { return x.p < e.p }) {
+				t.Fatalf("PopElement() = %v, not a min element of %v", e, ref)
+			}
+			ref = slices.Delete(ref, i, i+1)
+		default:
+			h.Compact()
+			checkLazy(t, h, len(ref), 0)
+		}
+		if h.Len() != len(ref) || h.Tombstones() != h.h.Len()-len(ref) {
+			t.Fatalf("Len() = %d, Tombstones() = %d with %d in the heap, want %d live",
+				h.Len(), h.Tombstones(), h.h.Len(), len(ref))
+		}
+	}
+}
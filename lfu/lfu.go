@@ -0,0 +1,153 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package lfu implements a least-frequently-used cache on top of an indexed heap.
+package lfu
+
+import (
+	"sync"
+
+	"github.com/iangudger/heap"
+)
+
+// A Cache holds up to a fixed number of entries, evicting the least frequently used entry when full. Ties in
+// frequency are broken by evicting the least recently used entry. It is safe for concurrent use.
+//
+// Entries are kept in a min-heap ordered by frequency and recency, so Get, Set and Delete take O(log n) time.
+type Cache[K comparable, V any] struct {
+	capacity int
+	onEvict  func(K, V)
+
+	mu    sync.Mutex
+	items map[K]*entry[K, V]
+	h     heap.Heap[*entry[K, V]]
+	tick  uint64
+}
+
+type entry[K comparable, V any] struct {
+	k     K
+	v     V
+	freq  uint64
+	last  uint64
+	index int
+}
+
+func (e *entry[K, V]) Less(o *entry[K, V]) bool {
+	if e.freq == o.freq {
+		return e.last < o.last
+	}
+	return e.freq < o.freq
+}
+
+func (e *entry[K, V]) SetIndex(i int) {
+	e.index = i
+}
+
+// New returns an empty Cache that holds up to capacity entries. If onEvict is not nil, it is called with each
+// entry evicted to make room for another, without the Cache's lock held. New panics if capacity is not
+// positive.
+func New[K comparable, V any](capacity int, onEvict func(k K, v V)) *Cache[K, V] {
+	if capacity <= 0 {
+		panic("lfu: capacity must be positive")
+	}
+	return &Cache[K, V]{
+		capacity: capacity,
+		onEvict:  onEvict,
+		items:    make(map[K]*entry[K, V]),
+	}
+}
+
+// Len returns the number of entries in the cache.
+func (c *Cache[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.items)
+}
+
+// Get returns the value stored under k and counts a use of it. It reports false if there is none.
+func (c *Cache[K, V]) Get(k K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.items[k]
+	if !ok {
+		var zero V
+		return zero, false
+	}
+	c.touch(e)
+	return e.v, true
+}
+
+// Peek returns the value stored under k without counting a use of it.
+func (c *Cache[K, V]) Peek(k K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.items[k]
+	if !ok {
+		var zero V
+		return zero, false
+	}
+	return e.v, true
+}
+
+// Frequency returns the number of uses counted for the entry stored under k, or 0 if there is none. Storing
+// an entry counts as its first use.
+func (c *Cache[K, V]) Frequency(k K) uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.items[k]; ok {
+		return e.freq
+	}
+	return 0
+}
+
+// Set stores v under k and counts a use of it, evicting the least frequently used entry if the cache is
+// full.
+func (c *Cache[K, V]) Set(k K, v V) {
+	c.mu.Lock()
+	if e, ok := c.items[k]; ok {
+		e.v = v
+		c.touch(e)
+		c.mu.Unlock()
+		return
+	}
+
+	var (
+		evicted *entry[K, V]
+		ok      bool
+	)
+	if len(c.items) >= c.capacity {
+		evicted = c.h.MustPopElement()
+		delete(c.items, evicted.k)
+		ok = true
+	}
+	c.tick++
+	e := &entry[K, V]{k: k, v: v, freq: 1, last: c.tick}
+	c.items[k] = e
+	c.h.PushElement(e)
+	c.mu.Unlock()
+
+	if ok && c.onEvict != nil {
+		c.onEvict(evicted.k, evicted.v)
+	}
+}
+
+// Delete removes the entry stored under k without calling onEvict. It reports whether there was one.
+func (c *Cache[K, V]) Delete(k K) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.items[k]
+	if ok {
+		delete(c.items, k)
+		c.h.RemoveElement(e.index)
+	}
+	return ok
+}
+
+// touch counts a use of e. The caller must hold c.mu.
+func (c *Cache[K, V]) touch(e *entry[K, V]) {
+	c.tick++
+	e.freq++
+	e.last = c.tick
+	c.h.Fix(e.index)
+}
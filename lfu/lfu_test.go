@@ -0,0 +1,190 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package lfu
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+type eviction struct {
+	k string
+	v int
+}
+
+func newRecording(capacity int) (*Cache[string, int], *[]eviction) {
+	var evicted []eviction
+	c := New(capacity, func(k string, v int) {
+		evicted = append(evicted, eviction{k, v})
+	})
+	return c, &evicted
+}
+
+func TestFrequencyOrder(t *testing.T) {
+	c, evicted := newRecording(3)
+	c.Set("a", 1)
+	c.Set("b", 2)
+	c.Set("c", 3)
+	for range 3 {
+		c.Get("a")
+	}
+	c.Get("c")
+	c.Get("b")
+	c.Get("b")
+
+	if f := c.Frequency("a"); f != 4 {
+		t.Errorf("Frequency(a) = %d, want 4", f)
+	}
+	c.Set("d", 4) // evicts c, used twice
+	c.Get("d")
+	c.Get("d")
+	c.Set("e", 5) // evicts b rather than d; both were used three times, but d more recently
+	want := []eviction{{"c", 3}, {"b", 2}}
+	if fmt.Sprint(*evicted) != fmt.Sprint(want) {
+		t.Errorf("evicted %v, want %v", *evicted, want)
+	}
+}
+
+func TestRecencyTieBreak(t *testing.T) {
+	c, evicted := newRecording(3)
+	c.Set("a", 1)
+	c.Set("b", 2)
+	c.Set("c", 3)
+	c.Get("a")
+	c.Get("b")
+	c.Get("c")
+	c.Get("a")
+	c.Get("c")
+	c.Get("b")
+	// All entries have been used three times; a was used least recently.
+	c.Set("d", 4)
+	if want := []eviction{{"a", 1}}; fmt.Sprint(*evicted) != fmt.Sprint(want) {
+		t.Errorf("evicted %v, want %v", *evicted, want)
+	}
+}
+
+func TestEvictionAtCapacity(t *testing.T) {
+	c, evicted := newRecording(2)
+	c.Set("a", 1)
+	c.Set("b", 2)
+	if len(*evicted) != 0 {
+		t.Fatalf("evicted %v below capacity", *evicted)
+	}
+	c.Set("a", 10) // updating an entry does not evict
+	if len(*evicted) != 0 || c.Len() != 2 {
+		t.Fatalf("after update: evicted %v, Len() = %d", *evicted, c.Len())
+	}
+	if v, _ := c.Peek("a"); v != 10 {
+		t.Errorf("Peek(a) = %d, want 10", v)
+	}
+	c.Set("c", 3)
+	if c.Len() != 2 {
+		t.Errorf("Len() = %d, want 2", c.Len())
+	}
+	if want := []eviction{{"b", 2}}; fmt.Sprint(*evicted) != fmt.Sprint(want) {
+		t.Errorf("evicted %v, want %v", *evicted, want)
+	}
+	if _, ok := c.Get("b"); ok {
+		t.Error("Get(b) found an evicted entry")
+	}
+}
+
+func TestPeekDoesNotCount(t *testing.T) {
+	c, _ := newRecording(2)
+	c.Set("a", 1)
+	c.Peek("a")
+	if f := c.Frequency("a"); f != 1 {
+		t.Errorf("Frequency(a) = %d after Peek, want 1", f)
+	}
+}
+
+func TestNilOnEvict(t *testing.T) {
+	c := New[string, int](1, nil)
+	c.Set("a", 1)
+	c.Set("b", 2)
+	if v, ok := c.Get("b"); !ok || v != 2 {
+		t.Errorf("Get(b) = %d, %t, want 2, true", v, ok)
+	}
+}
+
+func TestDeleteThenSet(t *testing.T) {
+	c, evicted := newRecording(2)
+	c.Set("a", 1)
+	c.Set("b", 2)
+	c.Get("a")
+	c.Get("a")
+	if !c.Delete("a") {
+		t.Fatal("Delete(a) reported false")
+	}
+	if c.Delete("a") {
+		t.Error("second Delete(a) reported true")
+	}
+	if c.Len() != 1 || c.Frequency("a") != 0 {
+		t.Errorf("after Delete: Len() = %d, Frequency(a) = %d", c.Len(), c.Frequency("a"))
+	}
+
+	c.Set("a", 3)
+	if f := c.Frequency("a"); f != 1 {
+		t.Errorf("Frequency(a) = %d after Delete and Set, want 1", f)
+	}
+	c.Set("c", 4) // a and b are both used once; b less recently
+	if want := []eviction{{"b", 2}}; fmt.Sprint(*evicted) != fmt.Sprint(want) {
+		t.Errorf("evicted %v, want %v", *evicted, want)
+	}
+	if v, ok := c.Get("a"); !ok || v != 3 {
+		t.Errorf("Get(a) = %d, %t, want 3, true", v, ok)
+	}
+}
+
+func TestNewPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("New(0) did not panic")
+		}
+	}()
+	New[string, int](0, nil)
+}
+
+func TestConcurrent(t *testing.T) {
+	const capacity = 16
+	var (
+		mu      sync.Mutex
+		evicted int
+	)
+	c := New(capacity, func(int, int) {
+		mu.Lock()
+		evicted++
+		mu.Unlock()
+	})
+
+	var wg sync.WaitGroup
+	for g := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range 1000 {
+				k := (g*7 + i) % 64
+				if v, ok := c.Get(k); ok && v != k {
+					t.Errorf("Get(%d) = %d", k, v)
+				}
+				c.Set(k, k)
+				if i%10 == 0 {
+					c.Delete(k)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if n := c.Len(); n > capacity {
+		t.Errorf("Len() = %d, want at most %d", n, capacity)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if evicted == 0 {
+		t.Error("no entries were evicted")
+	}
+}
@@ -0,0 +1,123 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package heap
+
+import (
+	"math/rand/v2"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// A MultiQueue is a relaxed priority queue that scales with concurrent use, at the cost of only approximate
+// ordering.
+//
+// It keeps c×P independently locked heaps, where P is GOMAXPROCS when the queue is created. Push adds to a
+// random heap. Pop samples two random heaps and pops the smaller of their minimums. Following Rihani,
+// Sanders and Dementiev, "MultiQueues: Simple Relaxed Concurrent Priority Queues" (2015), and the analysis
+// of Alistarh et al., "The Power of Choice in Priority Scheduling" (2017), the rank of a popped element
+// among all queued elements is O(c×P) in expectation and O(c×P log(c×P)) with high probability. Pop only
+// reports false after finding every heap empty.
+type MultiQueue[T Comparable[T]] struct {
+	shards []mqShard[T]
+	n      atomic.Int64
+}
+
+type mqShard[T Comparable[T]] struct {
+	mu sync.Mutex
+	h  Heap[T]
+
+	// Pad to keep shards on separate cache lines.
+	_ [64]byte
+}
+
+// NewMultiQueue returns an empty MultiQueue with c heaps per processor. A c of zero or less uses 2.
+func NewMultiQueue[T Comparable[T]](c int) *MultiQueue[T] {
+	if c <= 0 {
+		c = 2
+	}
+	return &MultiQueue[T]{shards: make([]mqShard[T], c*runtime.GOMAXPROCS(0))}
+}
+
+// Len returns the number of elements in the queue. It may be stale by the time it returns.
+func (q *MultiQueue[T]) Len() int {
+	return int(q.n.Load())
+}
+
+// Push adds an element to the queue.
+func (q *MultiQueue[T]) Push(e T) {
+	for {
+		s := &q.shards[rand.IntN(len(q.shards))]
+		if s.mu.TryLock() {
+			s.h.PushElement(e)
+			q.n.Add(1)
+			s.mu.Unlock()
+			return
+		}
+	}
+}
+
+// Pop removes and returns an element that is close to the minimum.
+func (q *MultiQueue[T]) Pop() (T, bool) {
+	for range len(q.shards) {
+		if q.n.Load() == 0 {
+			break
+		}
+		i, j := q.pair()
+		a, b := &q.shards[i], &q.shards[j]
+		if !a.mu.TryLock() {
+			continue
+		}
+		if a != b && !b.mu.TryLock() {
+			a.mu.Unlock()
+			continue
+		}
+		best := a
+		if b.h.Len() > 0 && (a.h.Len() == 0 || b.h.MustPeekElement().Less(a.h.MustPeekElement())) {
+			best = b
+		}
+		e, ok := best.h.PopElement()
+		if ok {
+			q.n.Add(-1)
+		}
+		a.mu.Unlock()
+		if a != b {
+			b.mu.Unlock()
+		}
+		if ok {
+			return e, true
+		}
+	}
+
+	// Sampling kept finding empty or busy heaps; fall back to checking each one in turn.
+	for i := range q.shards {
+		s := &q.shards[i]
+		s.mu.Lock()
+		e, ok := s.h.PopElement()
+		if ok {
+			q.n.Add(-1)
+		}
+		s.mu.Unlock()
+		if ok {
+			return e, true
+		}
+	}
+	var zero T
+	return zero, false
+}
+
+// pair returns the indexes of two distinct shards, or the same shard twice if there is only one.
+func (q *MultiQueue[T]) pair() (int, int) {
+	n := len(q.shards)
+	if n == 1 {
+		return 0, 0
+	}
+	i := rand.IntN(n)
+	j := rand.IntN(n - 1)
+	if j >= i {
+		j++
+	}
+	return i, j
+}
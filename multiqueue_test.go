@@ -0,0 +1,120 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package heap
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func testMultiQueue(t *testing.T, q *MultiQueue[keyed]) {
+	const (
+		workers = 8
+		each    = 2000
+	)
+	var (
+		wg     sync.WaitGroup
+		popped = make([][]keyed, workers)
+	)
+	// Phase 1: concurrent pushes and pops.
+	for w := range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range each {
+				q.Push(keyed{i % 100, w*each + i})
+				if i%2 == 1 {
+					if e, ok := q.Pop(); ok {
+						popped[w] = append(popped[w], e)
+					}
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Phase 2: concurrent pops only. Since nothing is pushed, a Pop that reports false must have found
+	// every element already taken.
+	var empty atomic.Bool
+	for w := range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				e, ok := q.Pop()
+				if !ok {
+					if n := q.Len(); n != 0 {
+						t.Errorf("Pop() reported false with Len() = %d", n)
+					}
+					empty.Store(true)
+					return
+				}
+				popped[w] = append(popped[w], e)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if !empty.Load() {
+		t.Fatal("no Pop reported false")
+	}
+	seen := make([]bool, workers*each)
+	total := 0
+	for _, p := range popped {
+		for _, e := range p {
+			if seen[e.id] {
+				t.Fatalf("element %v popped twice", e)
+			}
+			seen[e.id] = true
+			total++
+		}
+	}
+	if total != workers*each {
+		t.Errorf("popped %d elements, want %d", total, workers*each)
+	}
+	if _, ok := q.Pop(); ok {
+		t.Error("Pop() on empty queue reported true")
+	}
+}
+
+func TestMultiQueueConcurrent(t *testing.T) {
+	testMultiQueue(t, NewMultiQueue[keyed](0))
+}
+
+func TestMultiQueueSingleShard(t *testing.T) {
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(1))
+	q := NewMultiQueue[keyed](1)
+	if n := len(q.shards); n != 1 {
+		t.Fatalf("queue has %d shards, want 1", n)
+	}
+	testMultiQueue(t, q)
+}
+
+func TestMultiQueueSequential(t *testing.T) {
+	q := NewMultiQueue[intElem](0)
+	q.Push(7)
+	if e, ok := q.Pop(); !ok || e != 7 {
+		t.Fatalf("Pop() = %d, %t, want 7, true", e, ok)
+	}
+	for i := range 1000 {
+		q.Push(intElem(i))
+	}
+	if n := q.Len(); n != 1000 {
+		t.Errorf("Len() = %d, want 1000", n)
+	}
+	sum := 0
+	for range 1000 {
+		e, ok := q.Pop()
+		if !ok {
+			t.Fatal("Pop() reported false before the queue was empty")
+		}
+		sum += int(e)
+	}
+	if want := 999 * 1000 / 2; sum != want {
+		t.Errorf("popped elements sum to %d, want %d", sum, want)
+	}
+}
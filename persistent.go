@@ -0,0 +1,94 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package heap
+
+// A Persistent is an immutable min-heap. Operations that modify the heap return a new version that shares
+// structure with the old one, so earlier versions remain valid and taking a snapshot is free.
+//
+// It is implemented as a leftist heap: Push, Pop and Merge take O(log n) time and allocate O(log n) nodes.
+// The zero value is an empty heap.
+type Persistent[T Comparable[T]] struct {
+	root *leftistNode[T]
+}
+
+type leftistNode[T Comparable[T]] struct {
+	v           T
+	left, right *leftistNode[T]
+
+	// rank is the length of the right spine.
+	rank int
+	size int
+}
+
+func (n *leftistNode[T]) getRank() int {
+	if n == nil {
+		return 0
+	}
+	return n.rank
+}
+
+func (n *leftistNode[T]) getSize() int {
+	if n == nil {
+		return 0
+	}
+	return n.size
+}
+
+func mergeLeftist[T Comparable[T]](a, b *leftistNode[T]) *leftistNode[T] {
+	if a == nil {
+		return b
+	}
+	if b == nil {
+		return a
+	}
+	if b.v.Less(a.v) {
+		a, b = b, a
+	}
+	left, right := a.left, mergeLeftist(a.right, b)
+	if left.getRank() < right.getRank() {
+		left, right = right, left
+	}
+	return &leftistNode[T]{
+		v:     a.v,
+		left:  left,
+		right: right,
+		rank:  right.getRank() + 1,
+		size:  a.size + b.size,
+	}
+}
+
+// Len returns the number of elements in the heap.
+func (p Persistent[T]) Len() int {
+	return p.root.getSize()
+}
+
+// Push returns a heap containing the elements of p and v.
+func (p Persistent[T]) Push(v T) Persistent[T] {
+	return Persistent[T]{mergeLeftist(p.root, &leftistNode[T]{v: v, rank: 1, size: 1})}
+}
+
+// Peek returns the min element in the heap.
+func (p Persistent[T]) Peek() (T, bool) {
+	if p.root == nil {
+		var zero T
+		return zero, false
+	}
+	return p.root.v, true
+}
+
+// Pop returns the min element in the heap and a heap containing the remaining elements. If p is empty, it
+// returns false and p.
+func (p Persistent[T]) Pop() (T, Persistent[T], bool) {
+	if p.root == nil {
+		var zero T
+		return zero, p, false
+	}
+	return p.root.v, Persistent[T]{mergeLeftist(p.root.left, p.root.right)}, true
+}
+
+// Merge returns a heap containing the elements of both p and o.
+func (p Persistent[T]) Merge(o Persistent[T]) Persistent[T] {
+	return Persistent[T]{mergeLeftist(p.root, o.root)}
+}
@@ -0,0 +1,119 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package heap
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+// drain pops every element of p, leaving p itself unchanged.
+func drain(p Persistent[intElem]) []intElem {
+	var out []intElem
+	for {
+		v, rest, ok := p.Pop()
+		if !ok {
+			return out
+		}
+		out = append(out, v)
+		p = rest
+	}
+}
+
+// checkLeftist checks the heap order, rank and size of every node under n.
+func checkLeftist(t *testing.T, n *leftistNode[intElem]) {
+	t.Helper()
+	if n == nil {
+		return
+	}
+	for _, c := range []*leftistNode[intElem]{n.left, n.right} {
+		if c != nil && c.v.Less(n.v) {
+			t.Fatalf("child %d is less than its parent %d", c.v, n.v)
+		}
+	}
+	if n.left.getRank() < n.right.getRank() || n.rank != n.right.getRank()+1 {
+		t.Fatalf("node %d has rank %d with children of rank %d and %d",
+			n.v, n.rank, n.left.getRank(), n.right.getRank())
+	}
+	if n.size != n.left.getSize()+n.right.getSize()+1 {
+		t.Fatalf("node %d has size %d with children of size %d and %d",
+			n.v, n.size, n.left.getSize(), n.right.getSize())
+	}
+	checkLeftist(t, n.left)
+	checkLeftist(t, n.right)
+}
+
+func TestPersistentVersions(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	versions := []Persistent[intElem]{{}}
+	refs := [][]intElem{nil}
+	for range 2000 {
+		i := r.Intn(len(versions))
+		p, ref := versions[i], refs[i]
+		switch r.Intn(3) {
+		case 0:
+			v := intElem(r.Intn(100))
+			p = p.Push(v)
+			ref = append(slices.Clone(ref), v)
+		case 1:
+			v, rest, ok := p.Pop()
+			if ok != (len(ref) > 0) {
+				t.Fatalf("Pop() reported %t with %d elements", ok, len(ref))
+			}
+			if !ok {
+				if rest != p {
+					t.Fatal("Pop() of an empty heap returned a different heap")
+				}
+				continue
+			}
+			if want := slices.Min(ref); v != want {
+				t.Fatalf("Pop() = %d, want %d", v, want)
+			}
+			p = rest
+			ref = slices.Clone(ref)
+			ref = slices.Delete(ref, slices.Index(ref, v), slices.Index(ref, v)+1)
+		case 2:
+			j := r.Intn(len(versions))
+			p = p.Merge(versions[j])
+			ref = append(slices.Clone(ref), refs[j]...)
+		}
+		if p.Len() != len(ref) {
+			t.Fatalf("Len() = %d, want %d", p.Len(), len(ref))
+		}
+		if len(ref) > 100 {
+			continue // keep versions small enough to check cheaply
+		}
+		versions = append(versions, p)
+		refs = append(refs, ref)
+	}
+
+	// Every version, including those that later versions were derived from, still holds its own elements.
+	for i, p := range versions {
+		checkLeftist(t, p.root)
+		want := slices.Clone(refs[i])
+		slices.Sort(want)
+		if got := drain(p); !slices.Equal(got, want) {
+			t.Fatalf("version %d holds %v, want %v", i, got, want)
+		}
+		if p.Len() != len(want) {
+			t.Fatalf("version %d has Len() = %d after draining, want %d", i, p.Len(), len(want))
+		}
+	}
+}
+
+func TestPersistentPeek(t *testing.T) {
+	var p Persistent[intElem]
+	if _, ok := p.Peek(); ok {
+		t.Error("Peek() on empty heap reported true")
+	}
+	q := p.Push(3).Push(1).Push(2)
+	if v, ok := q.Peek(); !ok || v != 1 {
+		t.Errorf("Peek() = %d, %t, want 1, true", v, ok)
+	}
+	if p.Len() != 0 {
+		t.Errorf("original heap has Len() = %d, want 0", p.Len())
+	}
+}
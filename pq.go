@@ -0,0 +1,106 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package heap
+
+import "cmp"
+
+// A PriorityQueue holds values of any type, each with a separate priority. Values with the lowest priority
+// are popped first.
+//
+// The zero value is an empty queue.
+type PriorityQueue[V any, P cmp.Ordered] struct {
+	h Heap[pqElement[V, P]]
+}
+
+// A PriorityItem is a handle to a value pushed onto a PriorityQueue.
+type PriorityItem[V any, P cmp.Ordered] struct {
+	value    V
+	priority P
+
+	// index is the item's position in the heap, or -1 once it has been removed.
+	index int
+}
+
+// Value returns the item's value.
+func (it *PriorityItem[V, P]) Value() V {
+	return it.value
+}
+
+// Priority returns the item's priority.
+func (it *PriorityItem[V, P]) Priority() P {
+	return it.priority
+}
+
+// pqElement is the heap element for a PriorityItem.
+type pqElement[V any, P cmp.Ordered] struct {
+	it *PriorityItem[V, P]
+}
+
+func (e pqElement[V, P]) Less(o pqElement[V, P]) bool {
+	return cmp.Less(e.it.priority, o.it.priority)
+}
+
+func (e pqElement[V, P]) SetIndex(i int) {
+	e.it.index = i
+}
+
+// Len returns the number of values in the queue.
+func (q *PriorityQueue[V, P]) Len() int {
+	return q.h.Len()
+}
+
+// Push adds value to the queue with the given priority and returns a handle to it.
+func (q *PriorityQueue[V, P]) Push(value V, priority P) *PriorityItem[V, P] {
+	it := &PriorityItem[V, P]{value: value, priority: priority}
+	q.h.PushElement(pqElement[V, P]{it})
+	return it
+}
+
+// Pop removes and returns the value with the lowest priority, along with its priority.
+func (q *PriorityQueue[V, P]) Pop() (V, P, bool) {
+	e, ok := q.h.PopElement()
+	if !ok {
+		var (
+			zv V
+			zp P
+		)
+		return zv, zp, false
+	}
+	return e.it.value, e.it.priority, true
+}
+
+// Peek returns the value with the lowest priority, along with its priority.
+func (q *PriorityQueue[V, P]) Peek() (V, P, bool) {
+	e, ok := q.h.PeekElement()
+	if !ok {
+		var (
+			zv V
+			zp P
+		)
+		return zv, zp, false
+	}
+	return e.it.value, e.it.priority, true
+}
+
+// Update changes the priority of it, which must have been returned by Push on q. It reports false if it has
+// already been removed from the queue.
+func (q *PriorityQueue[V, P]) Update(it *PriorityItem[V, P], priority P) bool {
+	if it.index < 0 {
+		return false
+	}
+	it.priority = priority
+	q.h.Fix(it.index)
+	return true
+}
+
+// Remove removes it, which must have been returned by Push on q, from the queue. It reports false if it has
+// already been removed.
+func (q *PriorityQueue[V, P]) Remove(it *PriorityItem[V, P]) bool {
+	if it.index < 0 {
+		return false
+	}
+	q.h.RemoveElement(it.index)
+	return true
+}
@@ -0,0 +1,47 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package heap
+
+import "testing"
+
+func TestPriorityQueue(t *testing.T) {
+	var q PriorityQueue[string, int]
+	a := q.Push("a", 3)
+	b := q.Push("b", 1)
+	c := q.Push("c", 2)
+	q.Push("d", 4)
+
+	if !q.Update(a, 0) {
+		t.Fatal("Update(a) reported false")
+	}
+	if !q.Remove(c) {
+		t.Fatal("Remove(c) reported false")
+	}
+	if q.Remove(c) || q.Update(c, 5) {
+		t.Error("Remove or Update of a removed item reported true")
+	}
+	if v, p, ok := q.Peek(); v != "a" || p != 0 || !ok {
+		t.Errorf("Peek() = %q, %d, %t, want a, 0, true", v, p, ok)
+	}
+
+	want := []struct {
+		v string
+		p int
+	}{{"a", 0}, {"b", 1}, {"d", 4}}
+	for _, w := range want {
+		if v, p, ok := q.Pop(); v != w.v || p != w.p || !ok {
+			t.Fatalf("Pop() = %q, %d, %t, want %q, %d, true", v, p, ok, w.v, w.p)
+		}
+	}
+	if _, _, ok := q.Pop(); ok {
+		t.Error("Pop() on empty queue reported true")
+	}
+	if q.Update(b, 0) {
+		t.Error("Update of a popped item reported true")
+	}
+	if b.Value() != "b" || b.Priority() != 1 {
+		t.Errorf("b = %q, %d", b.Value(), b.Priority())
+	}
+}
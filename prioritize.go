@@ -0,0 +1,98 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package heap
+
+import "context"
+
+// An OverflowPolicy determines what Prioritize does when its buffer is full.
+type OverflowPolicy int
+
+const (
+	// Block stops receiving from the input channel until a value is delivered.
+	Block OverflowPolicy = iota
+
+	// DropLowest discards the lowest-priority value among the buffered values and the one just received.
+	// Finding it takes time linear in the buffer size.
+	DropLowest
+)
+
+// PrioritizeOptions configures Prioritize.
+type PrioritizeOptions[T any] struct {
+	// Buffer is the maximum number of values held for reordering. Zero or less means unbounded.
+	Buffer int
+
+	// Overflow is the policy applied when Buffer values are held.
+	Overflow OverflowPolicy
+
+	// OnDrop, if not nil, is called with each value discarded by DropLowest.
+	OnDrop func(T)
+}
+
+// Prioritize returns a channel that delivers the values received from in, highest priority first: whenever
+// the output is ready, the buffered value that is least according to less is sent.
+//
+// Once in is closed, the remaining buffered values are delivered in priority order and the output channel is
+// closed. If ctx is done first, the output channel is closed and buffered values are discarded. A nil opts
+// uses an unbounded buffer.
+func Prioritize[T any](ctx context.Context, in <-chan T, less func(a, b T) bool, opts *PrioritizeOptions[T]) <-chan T {
+	if opts == nil {
+		opts = &PrioritizeOptions[T]{}
+	}
+	out := make(chan T)
+	go func() {
+		defer close(out)
+		h := NewFuncHeap(less)
+		for in != nil || h.Len() > 0 {
+			var (
+				recv = in
+				send chan<- T
+				top  T
+			)
+			if h.Len() > 0 {
+				send = out
+				top = h.MustPeekElement()
+			}
+			full := opts.Buffer > 0 && h.Len() >= opts.Buffer
+			if full && opts.Overflow == Block {
+				recv = nil
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case v, ok := <-recv:
+				if !ok {
+					in = nil
+					continue
+				}
+				if !full {
+					h.PushElement(v)
+					continue
+				}
+				if w := worst(h); less(v, h.At(w)) {
+					old := h.At(w)
+					h.Set(w, v)
+					v = old
+				}
+				if opts.OnDrop != nil {
+					opts.OnDrop(v)
+				}
+			case send <- top:
+				h.MustPopElement()
+			}
+		}
+	}()
+	return out
+}
+
+// worst returns the index of a greatest element of a non-empty heap. It is always a leaf.
+func worst[T any](h *FuncHeap[T]) int {
+	w := h.Len() / 2
+	for i := w + 1; i < h.Len(); i++ {
+		if h.less(h.s[w], h.s[i]) {
+			w = i
+		}
+	}
+	return w
+}
@@ -0,0 +1,120 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package heap
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+)
+
+func intLess(a, b int) bool {
+	return a < b
+}
+
+// collect reads out until it is closed.
+func collect(t *testing.T, out <-chan int) []int {
+	t.Helper()
+	var got []int
+	timeout := time.After(10 * time.Second)
+	for {
+		select {
+		case v, ok := <-out:
+			if !ok {
+				return got
+			}
+			got = append(got, v)
+		case <-timeout:
+			t.Fatal("output channel was not closed")
+		}
+	}
+}
+
+func TestPrioritizeOrder(t *testing.T) {
+	in := make(chan int)
+	out := Prioritize(context.Background(), in, intLess, nil)
+	// in is unbuffered and out is not read, so every value is buffered once its send completes.
+	vals := []int{5, 3, 8, 1, 9, 2, 7}
+	for _, v := range vals {
+		in <- v
+	}
+	close(in)
+	slices.Sort(vals)
+	if got := collect(t, out); !slices.Equal(got, vals) {
+		t.Errorf("got %v, want %v", got, vals)
+	}
+}
+
+func TestPrioritizeInterleaved(t *testing.T) {
+	in := make(chan int)
+	out := Prioritize(context.Background(), in, intLess, nil)
+	in <- 4
+	if v := <-out; v != 4 {
+		t.Fatalf("got %d, want 4", v)
+	}
+	in <- 6
+	in <- 2
+	close(in)
+	if got := collect(t, out); !slices.Equal(got, []int{2, 6}) {
+		t.Errorf("got %v, want [2 6]", got)
+	}
+}
+
+func TestPrioritizeCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	in := make(chan int)
+	out := Prioritize(ctx, in, intLess, nil)
+	in <- 1
+	in <- 2
+	cancel()
+	// Values may be delivered until the goroutine notices ctx is done, but out must then be closed even
+	// though in is still open.
+	if got := collect(t, out); len(got) > 2 {
+		t.Errorf("got %v after cancel", got)
+	}
+}
+
+func TestPrioritizeBlock(t *testing.T) {
+	in := make(chan int)
+	out := Prioritize(context.Background(), in, intLess, &PrioritizeOptions[int]{Buffer: 2})
+	in <- 3
+	in <- 1
+	select {
+	case in <- 2:
+		t.Fatal("send to a full Block buffer succeeded")
+	case <-time.After(50 * time.Millisecond):
+	}
+	if v := <-out; v != 1 {
+		t.Fatalf("got %d, want 1", v)
+	}
+	in <- 2
+	close(in)
+	if got := collect(t, out); !slices.Equal(got, []int{2, 3}) {
+		t.Errorf("got %v, want [2 3]", got)
+	}
+}
+
+func TestPrioritizeDropLowest(t *testing.T) {
+	var dropped []int
+	in := make(chan int)
+	out := Prioritize(context.Background(), in, intLess, &PrioritizeOptions[int]{
+		Buffer:   2,
+		Overflow: DropLowest,
+		OnDrop:   func(v int) { dropped = append(dropped, v) },
+	})
+	in <- 5
+	in <- 1
+	in <- 3 // displaces 5, the lowest priority
+	in <- 9 // lower priority than everything buffered, so dropped itself
+	close(in)
+	if got := collect(t, out); !slices.Equal(got, []int{1, 3}) {
+		t.Errorf("got %v, want [1 3]", got)
+	}
+	// OnDrop runs on the goroutine that closed out, so it is safe to read dropped now.
+	if !slices.Equal(dropped, []int{5, 9}) {
+		t.Errorf("dropped %v, want [5 9]", dropped)
+	}
+}
@@ -0,0 +1,270 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package heap
+
+import (
+	"math"
+	"time"
+)
+
+// A RunningQuantile tracks a quantile of a changing multiset of values.
+//
+// It keeps the values at or below the quantile in a max-heap and the rest in a min-heap, so adding a value
+// and reading the quantile take O(log n) time. Removed values are deleted lazily when they reach the top of
+// their heap, or when removed values make up more than half of a heap.
+//
+// The zero value is an empty RunningQuantile that tracks the median.
+type RunningQuantile[T interface {
+	Comparable[T]
+	comparable
+}] struct {
+	// q is the tracked quantile if set is true. Otherwise the median is tracked.
+	q   float64
+	set bool
+
+	// lo is a max-heap and hi is a min-heap.
+	lo, hi quantileHalf[T]
+}
+
+// NewRunningQuantile returns an empty RunningQuantile that tracks quantile q. The value reported is the
+// element at index floor(q*(n-1)) of the sorted values. It panics if q is not in the range [0, 1].
+func NewRunningQuantile[T interface {
+	Comparable[T]
+	comparable
+}](q float64) *RunningQuantile[T] {
+	if !(q >= 0 && q <= 1) {
+		panic("heap: quantile out of range")
+	}
+	return &RunningQuantile[T]{q: q, set: true}
+}
+
+// NewRunningMedian returns an empty RunningQuantile that tracks the median. For an even number of values,
+// the lower of the two middle values is reported.
+func NewRunningMedian[T interface {
+	Comparable[T]
+	comparable
+}]() *RunningQuantile[T] {
+	return NewRunningQuantile[T](0.5)
+}
+
+// Len returns the number of values.
+func (r *RunningQuantile[T]) Len() int {
+	return r.lo.n + r.hi.n
+}
+
+// Add adds v to the values.
+func (r *RunningQuantile[T]) Add(v T) {
+	if r.lo.n > 0 && !r.lo.top().Less(v) {
+		r.lo.push(v, true)
+	} else {
+		r.hi.push(v, false)
+	}
+	r.balance()
+}
+
+// Remove removes one copy of v from the values. It reports false if v is not present.
+func (r *RunningQuantile[T]) Remove(v T) bool {
+	if !r.lo.remove(v) && !r.hi.remove(v) {
+		return false
+	}
+	r.balance()
+	return true
+}
+
+// Value returns the value at the tracked quantile. It reports false if there are no values.
+func (r *RunningQuantile[T]) Value() (T, bool) {
+	if r.lo.n == 0 {
+		var zero T
+		return zero, false
+	}
+	return r.lo.top(), true
+}
+
+// balance moves values between the halves until the low half holds floor(q*(n-1))+1 of them.
+func (r *RunningQuantile[T]) balance() {
+	q := 0.5
+	if r.set {
+		q = r.q
+	}
+	want := 0
+	if n := r.Len(); n > 0 {
+		want = int(math.Floor(q*float64(n-1))) + 1
+	}
+	for r.lo.n > want {
+		r.hi.push(r.lo.pop(), false)
+	}
+	for r.lo.n < want {
+		r.lo.push(r.hi.pop(), true)
+	}
+}
+
+// quantileHalf is one of the two heaps of a RunningQuantile, with lazy deletion.
+type quantileHalf[T interface {
+	Comparable[T]
+	comparable
+}] struct {
+	h Heap[quantileElement[T]]
+
+	// n is the number of values that have not been removed.
+	n int
+
+	// live and dead count the copies of each value in h that have and have not been removed.
+	live, dead map[T]int
+}
+
+type quantileElement[T Comparable[T]] struct {
+	v   T
+	max bool
+}
+
+func (e quantileElement[T]) Less(o quantileElement[T]) bool {
+	if e.max {
+		return o.v.Less(e.v)
+	}
+	return e.v.Less(o.v)
+}
+
+// push adds v to the half, which is ordered as a max-heap if max is true and a min-heap otherwise. A half must
+// always be pushed to with the same max.
+func (s *quantileHalf[T]) push(v T, max bool) {
+	if s.live == nil {
+		s.live = make(map[T]int)
+		s.dead = make(map[T]int)
+	}
+	s.h.PushElement(quantileElement[T]{v, max})
+	s.live[v]++
+	s.n++
+}
+
+// prune pops removed values from the top of the heap.
+func (s *quantileHalf[T]) prune() {
+	for s.h.Len() > 0 {
+		v := s.h[0].v
+		if s.dead[v] == 0 {
+			return
+		}
+		s.h.MustPopElement()
+		decrement(s.dead, v)
+	}
+}
+
+// top returns the top value. The half must not be empty.
+func (s *quantileHalf[T]) top() T {
+	s.prune()
+	return s.h[0].v
+}
+
+// pop removes and returns the top value. The half must not be empty.
+func (s *quantileHalf[T]) pop() T {
+	s.prune()
+	v := s.h.MustPopElement().v
+	decrement(s.live, v)
+	s.n--
+	return v
+}
+
+func (s *quantileHalf[T]) remove(v T) bool {
+	if s.live[v] == 0 {
+		return false
+	}
+	decrement(s.live, v)
+	s.dead[v]++
+	s.n--
+	s.compact()
+	return true
+}
+
+// compact drops removed values once they make up more than half of the heap.
+func (s *quantileHalf[T]) compact() {
+	if s.h.Len() <= 2*s.n {
+		return
+	}
+	live := s.h[:0]
+	for _, e := range s.h {
+		if s.dead[e.v] > 0 {
+			decrement(s.dead, e.v)
+			continue
+		}
+		live = append(live, e)
+	}
+	clear(s.h[len(live):])
+	s.h = live
+	s.h.Init()
+}
+
+func decrement[K comparable](m map[K]int, k K) {
+	if m[k] <= 1 {
+		delete(m, k)
+	} else {
+		m[k]--
+	}
+}
+
+// A WindowQuantile tracks a quantile of the values added within a sliding time window.
+type WindowQuantile[T interface {
+	Comparable[T]
+	comparable
+}] struct {
+	r      *RunningQuantile[T]
+	window time.Duration
+	clock  Clock
+	added  []timedValue[T]
+}
+
+type timedValue[T any] struct {
+	v  T
+	at time.Time
+}
+
+// NewWindowQuantile returns an empty WindowQuantile that tracks quantile q of the values added in the last
+// window of time, as read from clock. A nil clock uses SystemClock. It panics if q is not in the range
+// [0, 1].
+func NewWindowQuantile[T interface {
+	Comparable[T]
+	comparable
+}](q float64, window time.Duration, clock Clock) *WindowQuantile[T] {
+	if clock == nil {
+		clock = SystemClock{}
+	}
+	return &WindowQuantile[T]{
+		r:      NewRunningQuantile[T](q),
+		window: window,
+		clock:  clock,
+	}
+}
+
+// Len returns the number of values in the window.
+func (w *WindowQuantile[T]) Len() int {
+	w.evict()
+	return w.r.Len()
+}
+
+// Add adds v to the window.
+func (w *WindowQuantile[T]) Add(v T) {
+	w.evict()
+	w.added = append(w.added, timedValue[T]{v, w.clock.Now()})
+	w.r.Add(v)
+}
+
+// Value returns the value at the tracked quantile of the values in the window. It reports false if the
+// window is empty.
+func (w *WindowQuantile[T]) Value() (T, bool) {
+	w.evict()
+	return w.r.Value()
+}
+
+// evict removes values that have aged out of the window.
+func (w *WindowQuantile[T]) evict() {
+	cutoff := w.clock.Now().Add(-w.window)
+	i := 0
+	for ; i < len(w.added) && !w.added[i].at.After(cutoff); i++ {
+		w.r.Remove(w.added[i].v)
+	}
+	if i == 0 {
+		return
+	}
+	clear(w.added[:i])
+	w.added = w.added[i:]
+}
@@ -0,0 +1,106 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package heap
+
+import (
+	"math"
+	"math/rand"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestRunningQuantile(t *testing.T) {
+	for _, q := range []float64{0, 0.25, 0.5, 0.9, 1} {
+		r := rand.New(rand.NewSource(1))
+		rq := NewRunningQuantile[intElem](q)
+		var ref []intElem
+		for range 2000 {
+			if len(ref) > 0 && r.Intn(3) == 0 {
+				v := ref[r.Intn(len(ref))]
+				if !rq.Remove(v) {
+					t.Fatalf("q=%v: Remove(%d) reported false", q, v)
+				}
+				ref = slices.Delete(ref, slices.Index(ref, v), slices.Index(ref, v)+1)
+			} else {
+				v := intElem(r.Intn(100))
+				rq.Add(v)
+				ref = append(ref, v)
+			}
+			if rq.Len() != len(ref) {
+				t.Fatalf("q=%v: Len() = %d, want %d", q, rq.Len(), len(ref))
+			}
+			got, ok := rq.Value()
+			if len(ref) == 0 {
+				if ok {
+					t.Fatalf("q=%v: Value() on empty reported true", q)
+				}
+				continue
+			}
+			sorted := slices.Clone(ref)
+			slices.Sort(sorted)
+			if want := sorted[int(math.Floor(q*float64(len(ref)-1)))]; got != want {
+				t.Fatalf("q=%v: Value() = %d, want %d", q, got, want)
+			}
+		}
+		if rq.Remove(1000) {
+			t.Errorf("q=%v: Remove of absent value reported true", q)
+		}
+	}
+}
+
+func TestRunningQuantileZeroValue(t *testing.T) {
+	var r RunningQuantile[intElem]
+	for _, v := range []intElem{50, 1, 2, 3, 4, 5, 6} {
+		r.Add(v)
+	}
+	if got, _ := r.Value(); got != 4 {
+		t.Errorf("zero value Value() = %d, want median 4", got)
+	}
+}
+
+func TestRunningQuantileCompacts(t *testing.T) {
+	r := NewRunningMedian[intElem]()
+	for i := range 1000 {
+		r.Add(intElem(i))
+	}
+	for i := range 1000 {
+		// The smallest and largest values are buried at the bottoms of the two heaps, so pruning never reaches them.
+		if i < 250 || i >= 750 {
+			r.Remove(intElem(i))
+		}
+	}
+	for _, s := range []*quantileHalf[intElem]{&r.lo, &r.hi} {
+		if s.h.Len() > 2*s.n {
+			t.Errorf("half holds %d entries for %d values", s.h.Len(), s.n)
+		}
+	}
+	if got, _ := r.Value(); got != 499 {
+		t.Errorf("Value() = %d, want 499", got)
+	}
+}
+
+func TestWindowQuantile(t *testing.T) {
+	c := NewFakeClock(time.Unix(0, 0))
+	w := NewWindowQuantile[intElem](0.5, 10*time.Second, c)
+	for i := range 100 {
+		w.Add(intElem(i))
+		c.Advance(time.Second)
+	}
+	if n := w.Len(); n != 9 {
+		t.Errorf("Len() = %d, want 9", n)
+	}
+	if got, _ := w.Value(); got != 95 {
+		t.Errorf("Value() = %d, want 95", got)
+	}
+	lo, hi := w.r.lo.h.Len(), w.r.hi.h.Len()
+	if lo+hi > 2*w.Len()+2 {
+		t.Errorf("heaps hold %d entries for %d values", lo+hi, w.Len())
+	}
+	c.Advance(time.Minute)
+	if _, ok := w.Value(); ok {
+		t.Error("Value() on expired window reported true")
+	}
+}
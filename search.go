@@ -0,0 +1,71 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package heap
+
+// IndexFunc returns the index of the first element, in slice order, for which pred reports true, or -1 if
+// there is none.
+func (h *Heap[T]) IndexFunc(pred func(T) bool) int {
+	for i, e := range *h {
+		if pred(e) {
+			return i
+		}
+	}
+	return -1
+}
+
+// Index returns the index of an element of h equal to v, or -1 if there is none.
+//
+// Subtrees whose root is greater than v cannot contain v and are skipped, so elements near the top of the
+// heap are found quickly. This requires Less to be consistent with ==: equal elements must not be less than
+// each other.
+func Index[T interface {
+	Comparable[T]
+	comparable
+}](h *Heap[T], v T) int {
+	if h.Len() == 0 {
+		return -1
+	}
+	stack := []int{0}
+	for len(stack) > 0 {
+		i := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		e := (*h)[i]
+		if e == v {
+			return i
+		}
+		if v.Less(e) {
+			continue
+		}
+		if r := 2*i + 2; r < h.Len() {
+			stack = append(stack, r)
+		}
+		if l := 2*i + 1; l < h.Len() {
+			stack = append(stack, l)
+		}
+	}
+	return -1
+}
+
+// Contains reports whether h contains an element equal to v. See Index for the requirements on T.
+func Contains[T interface {
+	Comparable[T]
+	comparable
+}](h *Heap[T], v T) bool {
+	return Index(h, v) >= 0
+}
+
+// RemoveValue removes an element equal to v from h. It reports whether one was found. See Index for the
+// requirements on T.
+func RemoveValue[T interface {
+	Comparable[T]
+	comparable
+}](h *Heap[T], v T) bool {
+	i := Index(h, v)
+	if i < 0 {
+		return false
+	}
+	h.RemoveElement(i)
+	return true
+}
@@ -0,0 +1,58 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package heap
+
+// siftOps parameterizes the sift functions shared by the heap types in this package.
+type siftOps[T any] struct {
+	// less reports whether a must sort before b.
+	less func(a, b T) bool
+
+	// swapped, if not nil, is called after the elements at indexes i and j are exchanged.
+	swapped func(i, j int)
+
+	// setIndex, if not nil, is called with an element and its new index when a heap adds, moves or removes it
+	// other than by a sift. Removed elements are given index -1.
+	setIndex func(e T, i int)
+}
+
+// siftUp moves the element at index j towards the root until its parent is not greater than it.
+func siftUp[T any](s []T, j int, o siftOps[T]) {
+	for {
+		i := (j - 1) / 2 // parent
+		if i == j || !o.less(s[j], s[i]) {
+			break
+		}
+		s[i], s[j] = s[j], s[i]
+		if o.swapped != nil {
+			o.swapped(i, j)
+		}
+		j = i
+	}
+}
+
+// siftDown moves the element at index i0 towards the leaves of the first n elements until neither child is
+// less than it. It reports whether the element moved.
+func siftDown[T any](s []T, i0, n int, o siftOps[T]) bool {
+	i := i0
+	for {
+		j1 := 2*i + 1
+		if j1 >= n || j1 < 0 { // j1 < 0 after int overflow
+			break
+		}
+		j := j1 // left child
+		if j2 := j1 + 1; j2 < n && o.less(s[j2], s[j1]) {
+			j = j2 // right child
+		}
+		if !o.less(s[j], s[i]) {
+			break
+		}
+		s[i], s[j] = s[j], s[i]
+		if o.swapped != nil {
+			o.swapped(i, j)
+		}
+		i = j
+	}
+	return i > i0
+}
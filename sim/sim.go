@@ -0,0 +1,150 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package sim implements a discrete-event simulation engine on top of a heap-based future-event list.
+package sim
+
+import (
+	"time"
+
+	"github.com/iangudger/heap"
+)
+
+// An Event is a callback scheduled to run at a point in simulated time.
+type Event struct {
+	at  time.Duration
+	fn  func(*Engine)
+	seq uint64
+
+	// done is set once the event has run or been cancelled.
+	done bool
+}
+
+// At returns the simulated time at which the event runs.
+func (e *Event) At() time.Duration {
+	return e.at
+}
+
+// Less orders events by time, then by the order in which they were scheduled.
+func (e *Event) Less(o *Event) bool {
+	if e.at == o.at {
+		return e.seq < o.seq
+	}
+	return e.at < o.at
+}
+
+// An Engine runs events in simulated time order. Simulated time starts at zero and only advances when events
+// run. Events scheduled for the same time run in the order they were scheduled.
+//
+// Cancelled events are discarded lazily, and the future-event list is compacted once they make up more than
+// half of it.
+//
+// An Engine is not safe for concurrent use. Callbacks may schedule and cancel events on the Engine running
+// them.
+type Engine struct {
+	now     time.Duration
+	events  heap.Heap[*Event]
+	seq     uint64
+	pending int
+}
+
+// Now returns the current simulated time.
+func (s *Engine) Now() time.Duration {
+	return s.now
+}
+
+// Pending returns the number of events that have been scheduled but have not run or been cancelled.
+func (s *Engine) Pending() int {
+	return s.pending
+}
+
+// Schedule arranges for fn to run at simulated time at. It panics if at is before Now.
+func (s *Engine) Schedule(at time.Duration, fn func(*Engine)) *Event {
+	if at < s.now {
+		panic("sim: event scheduled in the past")
+	}
+	s.seq++
+	e := &Event{at: at, fn: fn, seq: s.seq}
+	s.events.PushElement(e)
+	s.pending++
+	return e
+}
+
+// After arranges for fn to run d after the current simulated time.
+func (s *Engine) After(d time.Duration, fn func(*Engine)) *Event {
+	return s.Schedule(s.now+d, fn)
+}
+
+// Cancel prevents e from running. It reports false if e has already run or been cancelled.
+func (s *Engine) Cancel(e *Event) bool {
+	if e.done {
+		return false
+	}
+	e.done = true
+	s.pending--
+	s.compact()
+	return true
+}
+
+// Step runs the next event, advancing simulated time to it. It reports false if no events are pending.
+func (s *Engine) Step() bool {
+	e, ok := s.next()
+	if !ok {
+		return false
+	}
+	s.events.MustPopElement()
+	e.done = true
+	s.pending--
+	s.now = e.at
+	e.fn(s)
+	return true
+}
+
+// RunUntil runs all events scheduled at or before t, then advances simulated time to t. Events scheduled by
+// callbacks are run if they fall within the limit.
+func (s *Engine) RunUntil(t time.Duration) {
+	for {
+		e, ok := s.next()
+		if !ok || e.at > t {
+			break
+		}
+		s.Step()
+	}
+	if t > s.now {
+		s.now = t
+	}
+}
+
+// Run runs events until none are pending.
+func (s *Engine) Run() {
+	for s.Step() {
+	}
+}
+
+// compact drops cancelled events once they make up more than half of the future-event list.
+func (s *Engine) compact() {
+	if s.events.Len() <= 2*s.pending {
+		return
+	}
+	live := s.events[:0]
+	for _, e := range s.events {
+		if !e.done {
+			live = append(live, e)
+		}
+	}
+	clear(s.events[len(live):])
+	s.events = live
+	s.events.Init()
+}
+
+// next discards cancelled events and returns the earliest pending one.
+func (s *Engine) next() (*Event, bool) {
+	for {
+		e, ok := s.events.PeekElement()
+		if !ok || !e.done {
+			return e, ok
+		}
+		s.events.MustPopElement()
+	}
+}
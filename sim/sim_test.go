@@ -0,0 +1,129 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sim
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestSameTimeFIFO(t *testing.T) {
+	var (
+		s   Engine
+		got []int
+	)
+	for i := range 10 {
+		s.Schedule(time.Second, func(*Engine) { got = append(got, i) })
+	}
+	s.Schedule(0, func(*Engine) { got = append(got, -1) })
+	s.Run()
+	if want := []int{-1, 0, 1, 2, 3, 4, 5, 6, 7, 8, 9}; !slices.Equal(got, want) {
+		t.Errorf("ran %v, want %v", got, want)
+	}
+	if s.Now() != time.Second {
+		t.Errorf("Now() = %v, want 1s", s.Now())
+	}
+}
+
+func TestCancel(t *testing.T) {
+	var (
+		s   Engine
+		got []string
+	)
+	a := s.After(time.Second, func(*Engine) { got = append(got, "a") })
+	s.After(2*time.Second, func(*Engine) { got = append(got, "b") })
+	c := s.After(3*time.Second, func(*Engine) { got = append(got, "c") })
+	s.After(time.Second/2, func(s *Engine) {
+		if !s.Cancel(c) {
+			t.Error("Cancel(c) from a callback reported false")
+		}
+	})
+	if !s.Cancel(a) {
+		t.Fatal("Cancel(a) reported false")
+	}
+	if s.Cancel(a) {
+		t.Error("second Cancel(a) reported true")
+	}
+	if n := s.Pending(); n != 3 {
+		t.Errorf("Pending() = %d, want 3", n)
+	}
+	s.Run()
+	if !slices.Equal(got, []string{"b"}) {
+		t.Errorf("ran %v, want [b]", got)
+	}
+	if n := s.Pending(); n != 0 {
+		t.Errorf("Pending() = %d, want 0", n)
+	}
+}
+
+func TestCancelCompacts(t *testing.T) {
+	var (
+		s   Engine
+		ran bool
+	)
+	s.After(time.Second, func(*Engine) { ran = true })
+	for i := range 1000 {
+		timeout := s.After(time.Hour+time.Duration(i), func(*Engine) { t.Error("cancelled timeout ran") })
+		s.Cancel(timeout)
+		if n := s.events.Len(); n > 2*s.Pending() {
+			t.Fatalf("future-event list holds %d events with %d pending", n, s.Pending())
+		}
+	}
+	s.Run()
+	if !ran {
+		t.Error("pending event did not run")
+	}
+}
+
+func TestRunUntil(t *testing.T) {
+	var (
+		s   Engine
+		got []string
+	)
+	log := func(name string) func(*Engine) {
+		return func(s *Engine) { got = append(got, fmt.Sprintf("%s@%v", name, s.Now())) }
+	}
+	s.Schedule(time.Second, func(s *Engine) {
+		log("a")(s)
+		s.After(time.Second, log("inside"))   // at 2s, within the limit
+		s.After(10*time.Second, log("later")) // at 11s, beyond it
+	})
+	s.Schedule(5*time.Second, log("limit"))
+
+	s.RunUntil(5 * time.Second)
+	if want := []string{"a@1s", "inside@2s", "limit@5s"}; !slices.Equal(got, want) {
+		t.Errorf("ran %v, want %v", got, want)
+	}
+	s.RunUntil(8 * time.Second)
+	if s.Now() != 8*time.Second {
+		t.Errorf("Now() = %v, want 8s", s.Now())
+	}
+	if n := s.Pending(); n != 1 {
+		t.Errorf("Pending() = %d, want 1", n)
+	}
+	s.RunUntil(7 * time.Second) // does not move time backwards
+	if s.Now() != 8*time.Second {
+		t.Errorf("Now() = %v after RunUntil in the past, want 8s", s.Now())
+	}
+	if !s.Step() || got[len(got)-1] != "later@11s" {
+		t.Errorf("Step ran %v, want later@11s last", got)
+	}
+	if s.Step() {
+		t.Error("Step() with nothing pending reported true")
+	}
+}
+
+func TestSchedulePastPanics(t *testing.T) {
+	var s Engine
+	s.RunUntil(time.Second)
+	defer func() {
+		if recover() == nil {
+			t.Error("Schedule in the past did not panic")
+		}
+	}()
+	s.Schedule(0, func(*Engine) {})
+}
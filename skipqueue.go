@@ -0,0 +1,225 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package heap
+
+import (
+	"math/bits"
+	"math/rand/v2"
+	"sync/atomic"
+)
+
+const (
+	// skipLevels is the maximum height of a SkipQueue node.
+	skipLevels = 32
+
+	// skipBoundOffset is the number of logically deleted nodes PopMin walks past before it tries to unlink
+	// them.
+	skipBoundOffset = 32
+)
+
+// A SkipQueue is a lock-free concurrent priority queue with linearizable Push, PopMin and PeekMin.
+//
+// It is the skiplist-based queue of Lindén and Jonsson, "A Skiplist-Based Concurrent Priority Queue with
+// Minimal Memory Contention" (2013). PopMin logically deletes the first node by marking the pointer to it,
+// so the deleted nodes always form a prefix of the list; they are unlinked in batches, which keeps
+// contention on the head of the list low. Elements that compare equal are popped in the order they were
+// pushed.
+//
+// The zero value is not usable; create a SkipQueue with NewSkipQueue.
+type SkipQueue[T Comparable[T]] struct {
+	head, tail *skipNode[T]
+	n          atomic.Int64
+}
+
+type skipNode[T Comparable[T]] struct {
+	v    T
+	tail bool
+
+	// next0 is the level 0 link. Its del flag marks the node it points to as deleted.
+	next0 atomic.Pointer[skipLink[T]]
+
+	// next holds the links for levels 1 and up.
+	next []atomic.Pointer[skipNode[T]]
+
+	// inserting is set until the node has been linked at every level.
+	inserting atomic.Bool
+}
+
+// skipLink is an immutable level 0 link. Replacing the whole link lets the pointer and the deletion mark be
+// updated together with a single compare-and-swap.
+type skipLink[T Comparable[T]] struct {
+	n   *skipNode[T]
+	del bool
+}
+
+// NewSkipQueue returns an empty SkipQueue.
+func NewSkipQueue[T Comparable[T]]() *SkipQueue[T] {
+	tail := &skipNode[T]{tail: true}
+	tail.next0.Store(&skipLink[T]{})
+	head := &skipNode[T]{next: make([]atomic.Pointer[skipNode[T]], skipLevels-1)}
+	head.next0.Store(&skipLink[T]{n: tail})
+	for i := range head.next {
+		head.next[i].Store(tail)
+	}
+	return &SkipQueue[T]{head: head, tail: tail}
+}
+
+// Len returns the number of elements in the queue. It may be stale by the time it returns.
+func (q *SkipQueue[T]) Len() int {
+	return int(q.n.Load())
+}
+
+// Push adds an element to the queue.
+func (q *SkipQueue[T]) Push(e T) {
+	height := 1 + bits.TrailingZeros64(rand.Uint64()|1<<(skipLevels-1))
+	n := &skipNode[T]{v: e, next: make([]atomic.Pointer[skipNode[T]], height-1)}
+	n.inserting.Store(true)
+
+	var preds, succs [skipLevels]*skipNode[T]
+	var del *skipNode[T]
+	for {
+		del = q.locatePreds(e, &preds, &succs)
+		n.next0.Store(&skipLink[T]{n: succs[0]})
+		l := preds[0].next0.Load()
+		if l.n == succs[0] && !l.del && preds[0].next0.CompareAndSwap(l, &skipLink[T]{n: n}) {
+			break
+		}
+	}
+	q.n.Add(1)
+
+	for i := 1; i < height; {
+		n.next[i-1].Store(succs[i])
+		if n.next0.Load().del || succs[i].next0.Load().del || del == succs[i] {
+			break
+		}
+		if preds[i].next[i-1].CompareAndSwap(succs[i], n) {
+			i++
+			continue
+		}
+		del = q.locatePreds(e, &preds, &succs)
+		if succs[0] != n {
+			break
+		}
+	}
+	n.inserting.Store(false)
+}
+
+// PopMin removes and returns the min element in the queue.
+func (q *SkipQueue[T]) PopMin() (T, bool) {
+	var (
+		x       = q.head
+		obsHead = q.head.next0.Load()
+		newHead *skipNode[T]
+		offset  int
+	)
+	for {
+		if x.next0.Load().n == q.tail {
+			var zero T
+			return zero, false
+		}
+		if newHead == nil && x.inserting.Load() {
+			newHead = x
+		}
+		l := markNext(x)
+		offset++
+		x = l.n
+		if !l.del {
+			break
+		}
+	}
+	q.n.Add(-1)
+	v := x.v
+	if offset < skipBoundOffset {
+		return v, true
+	}
+
+	if newHead == nil {
+		newHead = x
+	}
+	if q.head.next0.CompareAndSwap(obsHead, &skipLink[T]{n: newHead, del: true}) {
+		q.restructure()
+	}
+	return v, true
+}
+
+// PeekMin returns the min element in the queue.
+func (q *SkipQueue[T]) PeekMin() (T, bool) {
+	for x := q.head; ; {
+		l := x.next0.Load()
+		if l.n == q.tail {
+			var zero T
+			return zero, false
+		}
+		if !l.del {
+			return l.n.v, true
+		}
+		x = l.n
+	}
+}
+
+// markNext sets the deletion mark on the level 0 link of x and returns the link as it was before.
+func markNext[T Comparable[T]](x *skipNode[T]) *skipLink[T] {
+	for {
+		l := x.next0.Load()
+		if l.del || x.next0.CompareAndSwap(l, &skipLink[T]{n: l.n, del: true}) {
+			return l
+		}
+	}
+}
+
+// before reports whether n belongs before an element e being inserted. Equal elements are inserted after
+// existing ones.
+func (q *SkipQueue[T]) before(n *skipNode[T], e T) bool {
+	return !n.tail && !e.Less(n.v)
+}
+
+// locatePreds finds, at each level, the last node before where e belongs and the node after it, skipping
+// deleted nodes. It returns the last deleted node it passed at level 0, if any.
+func (q *SkipQueue[T]) locatePreds(e T, preds, succs *[skipLevels]*skipNode[T]) *skipNode[T] {
+	var del *skipNode[T]
+	pred := q.head
+	for i := skipLevels - 1; i >= 0; i-- {
+		cur, d := pred.link(i)
+		for q.before(cur, e) || cur.next0.Load().del || (i == 0 && d) {
+			if i == 0 && d {
+				del = cur
+			}
+			pred = cur
+			cur, d = pred.link(i)
+		}
+		preds[i] = pred
+		succs[i] = cur
+	}
+	return del
+}
+
+// link returns the successor of n at level i and, at level 0, whether it is marked as deleted.
+func (n *skipNode[T]) link(i int) (*skipNode[T], bool) {
+	if i == 0 {
+		l := n.next0.Load()
+		return l.n, l.del
+	}
+	return n.next[i-1].Load(), false
+}
+
+// restructure advances the upper-level links of the head past deleted nodes.
+func (q *SkipQueue[T]) restructure() {
+	pred := q.head
+	for i := skipLevels - 1; i > 0; {
+		h := q.head.next[i-1].Load()
+		cur := pred.next[i-1].Load()
+		if !h.next0.Load().del {
+			i--
+			continue
+		}
+		for cur.next0.Load().del {
+			pred = cur
+			cur = pred.next[i-1].Load()
+		}
+		if q.head.next[i-1].CompareAndSwap(h, pred.next[i-1].Load()) {
+			i--
+		}
+	}
+}
@@ -0,0 +1,132 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package heap
+
+import (
+	"math/rand"
+	"slices"
+	"sync"
+	"testing"
+)
+
+// keyed is ordered by k alone, so elements with equal keys are told apart by id.
+type keyed struct {
+	k, id int
+}
+
+func (a keyed) Less(b keyed) bool {
+	return a.k < b.k
+}
+
+func TestSkipQueueSequential(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	q := NewSkipQueue[keyed]()
+	var ref []keyed // sorted by k, then by push order
+	id := 0
+	for range 5000 {
+		if len(ref) > 0 && r.Intn(3) == 0 {
+			got, ok := q.PopMin()
+			if !ok || got != ref[0] {
+				t.Fatalf("PopMin() = %v, %t, want %v, true", got, ok, ref[0])
+			}
+			ref = ref[1:]
+		} else {
+			e := keyed{r.Intn(50), id}
+			id++
+			q.Push(e)
+			i, _ := slices.BinarySearchFunc(ref, e.k, func(x keyed, k int) int {
+				if x.k <= k {
+					return -1
+				}
+				return 1
+			})
+			ref = slices.Insert(ref, i, e)
+		}
+		if q.Len() != len(ref) {
+			t.Fatalf("Len() = %d, want %d", q.Len(), len(ref))
+		}
+		got, ok := q.PeekMin()
+		if len(ref) == 0 {
+			if ok {
+				t.Fatalf("PeekMin() on empty queue = %v, true", got)
+			}
+		} else if got != ref[0] {
+			t.Fatalf("PeekMin() = %v, want %v", got, ref[0])
+		}
+	}
+}
+
+func TestSkipQueueDrain(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	q := NewSkipQueue[keyed]()
+	for i := range 1000 {
+		q.Push(keyed{r.Intn(20), i})
+	}
+	var prev keyed
+	for i := range 1000 {
+		e, ok := q.PopMin()
+		if !ok {
+			t.Fatalf("PopMin() %d reported false", i)
+		}
+		if i > 0 && (e.k < prev.k || e.k == prev.k && e.id < prev.id) {
+			t.Fatalf("PopMin() = %v after %v", e, prev)
+		}
+		prev = e
+	}
+	if e, ok := q.PopMin(); ok {
+		t.Errorf("PopMin() on drained queue = %v, true", e)
+	}
+	if n := q.Len(); n != 0 {
+		t.Errorf("Len() = %d on drained queue", n)
+	}
+}
+
+func TestSkipQueueConcurrent(t *testing.T) {
+	const (
+		workers = 8
+		each    = 2000
+	)
+	q := NewSkipQueue[keyed]()
+	popped := make([][]keyed, workers)
+	var wg sync.WaitGroup
+	for w := range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r := rand.New(rand.NewSource(int64(w)))
+			for i := range each {
+				q.Push(keyed{r.Intn(100), w*each + i})
+				if i%2 == 1 {
+					if e, ok := q.PopMin(); ok {
+						popped[w] = append(popped[w], e)
+					}
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	var all []keyed
+	for _, p := range popped {
+		all = append(all, p...)
+	}
+	for {
+		e, ok := q.PopMin()
+		if !ok {
+			break
+		}
+		all = append(all, e)
+	}
+	if len(all) != workers*each {
+		t.Fatalf("got %d elements, want %d", len(all), workers*each)
+	}
+	seen := make([]bool, workers*each)
+	for _, e := range all {
+		if seen[e.id] {
+			t.Fatalf("element %v popped twice", e)
+		}
+		seen[e.id] = true
+	}
+}
@@ -0,0 +1,111 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package heap
+
+// A StableHeap is a min-heap that pops equal elements in the order they were pushed.
+//
+// Each element is tagged with a sequence number when it is pushed. Sequence numbers are compared with serial
+// number arithmetic, so the counter may wrap around as long as fewer than 1<<63 pushes separate the oldest
+// and newest elements in the heap.
+//
+// If T implements Indexed, elements are told their index whenever it changes, as in a Heap.
+type StableHeap[T Comparable[T]] struct {
+	h   Heap[stableElement[T]]
+	seq uint64
+}
+
+type stableElement[T Comparable[T]] struct {
+	v   T
+	seq uint64
+}
+
+func (e stableElement[T]) Less(o stableElement[T]) bool {
+	if e.v.Less(o.v) {
+		return true
+	}
+	if o.v.Less(e.v) {
+		return false
+	}
+	return int64(e.seq-o.seq) < 0
+}
+
+// Len returns the number of elements in the heap.
+func (h *StableHeap[T]) Len() int {
+	return h.h.Len()
+}
+
+// At returns the element at index i.
+func (h *StableHeap[T]) At(i int) T {
+	return h.h[i].v
+}
+
+// Fix re-establishes the heap ordering after the element at index i has changed its value. The element keeps
+// its original position among equal elements.
+func (h *StableHeap[T]) Fix(i int) {
+	h.h.fix(i, h.ops())
+}
+
+// Update replaces the element at index i with v and re-establishes the heap ordering. The new element takes
+// the position among equal elements of the one it replaces.
+func (h *StableHeap[T]) Update(i int, v T) {
+	if isIndexed[T]() {
+		setIndex(h.h[i].v, -1)
+		setIndex(v, i)
+	}
+	h.h[i].v = v
+	h.h.fix(i, h.ops())
+}
+
+// PushElement adds an element to the heap.
+func (h *StableHeap[T]) PushElement(e T) {
+	h.h.push(stableElement[T]{v: e, seq: h.seq}, h.ops())
+	h.seq++
+}
+
+// MustPopElement removes and returns the min element in the heap. It panics if no elements are in the heap.
+func (h *StableHeap[T]) MustPopElement() T {
+	return h.h.popMin(h.ops()).v
+}
+
+// PopElement removes and returns the min element in the heap.
+func (h *StableHeap[T]) PopElement() (T, bool) {
+	if h.Len() == 0 {
+		var zero T
+		return zero, false
+	}
+	return h.MustPopElement(), true
+}
+
+// MustPeekElement returns the min element in the heap. It panics if no elements are in the heap.
+func (h *StableHeap[T]) MustPeekElement() T {
+	return h.h.MustPeekElement().v
+}
+
+// PeekElement returns the min element in the heap.
+func (h *StableHeap[T]) PeekElement() (T, bool) {
+	e, ok := h.h.PeekElement()
+	return e.v, ok
+}
+
+// RemoveElement removes and returns the element at index i from the heap.
+func (h *StableHeap[T]) RemoveElement(i int) T {
+	return h.h.remove(i, h.ops()).v
+}
+
+// ops returns the siftOps that order h. If T is Indexed, they forward index changes to the elements, since
+// stableElement itself is not Indexed.
+func (h *StableHeap[T]) ops() siftOps[stableElement[T]] {
+	o := siftOps[stableElement[T]]{less: stableElement[T].Less}
+	if isIndexed[T]() {
+		o.swapped = func(i, j int) {
+			setIndex(h.h[i].v, i)
+			setIndex(h.h[j].v, j)
+		}
+		o.setIndex = func(e stableElement[T], i int) {
+			setIndex(e.v, i)
+		}
+	}
+	return o
+}
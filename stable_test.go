@@ -0,0 +1,82 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package heap
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestStableHeapOrder(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	h := StableHeap[keyed]{seq: 1<<64 - 100} // exercise wrap-around
+	for i := range 1000 {
+		h.PushElement(keyed{r.Intn(20), i})
+	}
+	var prev keyed
+	for i := range 1000 {
+		e := h.MustPopElement()
+		if i > 0 && (e.k < prev.k || e.k == prev.k && e.id < prev.id) {
+			t.Fatalf("MustPopElement() = %v after %v", e, prev)
+		}
+		prev = e
+	}
+	if _, ok := h.PopElement(); ok {
+		t.Error("PopElement on empty heap reported true")
+	}
+}
+
+func checkStableIndexes(t *testing.T, h *StableHeap[*ptrElem]) {
+	t.Helper()
+	for i := range h.Len() {
+		if e := h.At(i); e.index != i {
+			t.Fatalf("element at %d has index %d", i, e.index)
+		}
+	}
+}
+
+func TestStableHeapIndexed(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	var (
+		h   StableHeap[*ptrElem]
+		all []*ptrElem
+	)
+	for range 200 {
+		e := &ptrElem{p: r.Intn(10)}
+		all = append(all, e)
+		h.PushElement(e)
+	}
+	checkStableIndexes(t, &h)
+
+	for _, e := range all {
+		if e.index < 0 {
+			continue
+		}
+		switch r.Intn(4) {
+		case 0:
+			h.RemoveElement(e.index)
+			if e.index != -1 {
+				t.Fatalf("removed element has index %d, want -1", e.index)
+			}
+		case 1:
+			e.p = r.Intn(10)
+			h.Fix(e.index)
+		case 2:
+			n := &ptrElem{p: r.Intn(10)}
+			h.Update(e.index, n)
+			if e.index != -1 {
+				t.Fatalf("replaced element has index %d, want -1", e.index)
+			}
+		}
+		checkStableIndexes(t, &h)
+	}
+
+	for h.Len() > 0 {
+		if e := h.MustPopElement(); e.index != -1 {
+			t.Fatalf("popped element has index %d, want -1", e.index)
+		}
+		checkStableIndexes(t, &h)
+	}
+}
@@ -0,0 +1,207 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package ttlcache implements a map whose entries expire, using a heap of expiry times.
+package ttlcache
+
+import (
+	"sync"
+	"time"
+
+	"github.com/iangudger/heap"
+)
+
+// Options configures a Cache.
+type Options[K comparable, V any] struct {
+	// Clock is the source of time for expiry. Nil uses heap.SystemClock.
+	Clock heap.Clock
+
+	// OnEvict, if not nil, is called with each entry that expires. It is called without the Cache's lock held,
+	// so it may use the Cache.
+	OnEvict func(k K, v V)
+}
+
+// A Cache is a map whose entries expire after a time-to-live. It is safe for concurrent use.
+//
+// Expired entries are never returned. They are removed, and OnEvict called, when they are looked up, when
+// Sweep is called, or by the janitor goroutine started with StartJanitor.
+type Cache[K comparable, V any] struct {
+	clock   heap.Clock
+	onEvict func(K, V)
+
+	mu      sync.Mutex
+	items   map[K]*entry[K, V]
+	expiry  heap.Heap[*entry[K, V]]
+	stop    chan struct{}
+	stopped chan struct{}
+}
+
+type entry[K comparable, V any] struct {
+	k       K
+	v       V
+	expires time.Time
+
+	// index is the entry's position in the expiry heap, or -1 if it does not expire.
+	index int
+}
+
+func (e *entry[K, V]) Less(o *entry[K, V]) bool {
+	return e.expires.Before(o.expires)
+}
+
+func (e *entry[K, V]) SetIndex(i int) {
+	e.index = i
+}
+
+// New returns an empty Cache. A nil opts uses the defaults.
+func New[K comparable, V any](opts *Options[K, V]) *Cache[K, V] {
+	if opts == nil {
+		opts = &Options[K, V]{}
+	}
+	c := &Cache[K, V]{
+		clock:   opts.Clock,
+		onEvict: opts.OnEvict,
+		items:   make(map[K]*entry[K, V]),
+	}
+	if c.clock == nil {
+		c.clock = heap.SystemClock{}
+	}
+	return c
+}
+
+// Len returns the number of entries in the cache, including expired entries that have not been removed.
+func (c *Cache[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.items)
+}
+
+// Set stores v under k, expiring after ttl. A ttl of zero or less means the entry does not expire.
+func (c *Cache[K, V]) Set(k K, v V, ttl time.Duration) {
+	var expires time.Time
+	if ttl > 0 {
+		expires = c.clock.Now().Add(ttl)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.items[k]
+	if !ok {
+		e = &entry[K, V]{k: k, index: -1}
+		c.items[k] = e
+	}
+	e.v = v
+	e.expires = expires
+	switch {
+	case ttl <= 0 && e.index >= 0:
+		c.expiry.RemoveElement(e.index)
+	case ttl > 0 && e.index >= 0:
+		c.expiry.Fix(e.index)
+	case ttl > 0:
+		c.expiry.PushElement(e)
+	}
+}
+
+// Get returns the value stored under k. It reports false if there is none or it has expired.
+func (c *Cache[K, V]) Get(k K) (V, bool) {
+	now := c.clock.Now()
+	c.mu.Lock()
+	e, ok := c.items[k]
+	if !ok {
+		c.mu.Unlock()
+		var zero V
+		return zero, false
+	}
+	if e.index < 0 || e.expires.After(now) {
+		c.mu.Unlock()
+		return e.v, true
+	}
+	c.remove(e)
+	c.mu.Unlock()
+
+	if c.onEvict != nil {
+		c.onEvict(e.k, e.v)
+	}
+	var zero V
+	return zero, false
+}
+
+// Delete removes the entry stored under k without calling OnEvict. It reports whether there was one.
+func (c *Cache[K, V]) Delete(k K) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.items[k]
+	if ok {
+		c.remove(e)
+	}
+	return ok
+}
+
+// Sweep removes every entry that has expired as of now, calling OnEvict for each, and returns how many were
+// removed. It takes O(m log n) time to remove m of n entries.
+func (c *Cache[K, V]) Sweep(now time.Time) int {
+	var evicted []*entry[K, V]
+	c.mu.Lock()
+	for {
+		e, ok := c.expiry.PeekElement()
+		if !ok || e.expires.After(now) {
+			break
+		}
+		c.remove(e)
+		evicted = append(evicted, e)
+	}
+	c.mu.Unlock()
+
+	if c.onEvict != nil {
+		for _, e := range evicted {
+			c.onEvict(e.k, e.v)
+		}
+	}
+	return len(evicted)
+}
+
+// StartJanitor starts a goroutine that calls Sweep every interval until Close is called. It panics if a
+// janitor is already running.
+func (c *Cache[K, V]) StartJanitor(interval time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.stop != nil {
+		panic("ttlcache: janitor already running")
+	}
+	stop, stopped := make(chan struct{}), make(chan struct{})
+	c.stop, c.stopped = stop, stopped
+	go func() {
+		defer close(stopped)
+		for {
+			t := c.clock.NewTimer(interval)
+			select {
+			case <-stop:
+				t.Stop()
+				return
+			case <-t.C():
+				c.Sweep(c.clock.Now())
+			}
+		}
+	}()
+}
+
+// Close stops the janitor goroutine, if one is running, and waits for it to exit.
+func (c *Cache[K, V]) Close() {
+	c.mu.Lock()
+	stop, stopped := c.stop, c.stopped
+	c.stop, c.stopped = nil, nil
+	c.mu.Unlock()
+	if stop != nil {
+		close(stop)
+		<-stopped
+	}
+}
+
+// remove deletes e from the cache. The caller must hold c.mu.
+func (c *Cache[K, V]) remove(e *entry[K, V]) {
+	delete(c.items, e.k)
+	if e.index >= 0 {
+		c.expiry.RemoveElement(e.index)
+	}
+}
@@ -0,0 +1,206 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ttlcache
+
+import (
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/iangudger/heap"
+)
+
+var epoch = time.Unix(1_700_000_000, 0)
+
+type recorder struct {
+	mu      sync.Mutex
+	evicted []string
+	ch      chan string
+}
+
+func (r *recorder) onEvict(k string, _ int) {
+	r.mu.Lock()
+	r.evicted = append(r.evicted, k)
+	r.mu.Unlock()
+	if r.ch != nil {
+		r.ch <- k
+	}
+}
+
+func (r *recorder) get() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return slices.Clone(r.evicted)
+}
+
+func newCache() (*Cache[string, int], *heap.FakeClock, *recorder) {
+	clock := heap.NewFakeClock(epoch)
+	r := &recorder{}
+	c := New(&Options[string, int]{Clock: clock, OnEvict: r.onEvict})
+	return c, clock, r
+}
+
+func TestExpiry(t *testing.T) {
+	c, clock, r := newCache()
+	c.Set("a", 1, time.Second)
+	c.Set("forever", 2, 0)
+	if v, ok := c.Get("a"); !ok || v != 1 {
+		t.Fatalf("Get(a) = %d, %t, want 1, true", v, ok)
+	}
+
+	clock.Advance(time.Second)
+	if _, ok := c.Get("a"); ok {
+		t.Error("Get(a) found an expired entry")
+	}
+	if v, ok := c.Get("forever"); !ok || v != 2 {
+		t.Errorf("Get(forever) = %d, %t, want 2, true", v, ok)
+	}
+	if got := r.get(); !slices.Equal(got, []string{"a"}) {
+		t.Errorf("evicted %v, want [a]", got)
+	}
+	if n := c.Len(); n != 1 {
+		t.Errorf("Len() = %d, want 1", n)
+	}
+	if _, ok := c.Get("missing"); ok {
+		t.Error("Get(missing) reported true")
+	}
+}
+
+func TestSetChangesTTL(t *testing.T) {
+	c, clock, _ := newCache()
+	c.Set("a", 1, time.Second)
+	c.Set("b", 2, 2*time.Second)
+	c.Set("a", 3, 3*time.Second) // moves a behind b in the expiry heap
+
+	clock.Advance(2 * time.Second)
+	if v, ok := c.Get("a"); !ok || v != 3 {
+		t.Errorf("Get(a) = %d, %t, want 3, true", v, ok)
+	}
+	if _, ok := c.Get("b"); ok {
+		t.Error("Get(b) found an expired entry")
+	}
+
+	c.Set("a", 4, time.Hour) // extends a again
+	clock.Advance(time.Minute)
+	if v, ok := c.Get("a"); !ok || v != 4 {
+		t.Errorf("Get(a) = %d, %t, want 4, true", v, ok)
+	}
+}
+
+func TestSetWithoutTTL(t *testing.T) {
+	c, clock, r := newCache()
+	c.Set("a", 1, time.Second)
+	c.Set("b", 2, time.Second)
+	c.Set("a", 3, 0)
+	c.mu.Lock()
+	n := c.expiry.Len()
+	c.mu.Unlock()
+	if n != 1 {
+		t.Fatalf("expiry heap holds %d entries, want 1", n)
+	}
+
+	clock.Advance(time.Hour)
+	if got := c.Sweep(clock.Now()); got != 1 {
+		t.Errorf("Sweep() = %d, want 1", got)
+	}
+	if v, ok := c.Get("a"); !ok || v != 3 {
+		t.Errorf("Get(a) = %d, %t, want 3, true", v, ok)
+	}
+	if got := r.get(); !slices.Equal(got, []string{"b"}) {
+		t.Errorf("evicted %v, want [b]", got)
+	}
+}
+
+func TestDelete(t *testing.T) {
+	c, clock, r := newCache()
+	c.Set("a", 1, time.Second)
+	c.Set("b", 2, 0)
+	if !c.Delete("a") || !c.Delete("b") {
+		t.Fatal("Delete reported false")
+	}
+	if c.Delete("a") {
+		t.Error("second Delete(a) reported true")
+	}
+	clock.Advance(time.Hour)
+	if got := c.Sweep(clock.Now()); got != 0 {
+		t.Errorf("Sweep() = %d after Delete, want 0", got)
+	}
+	if got := r.get(); len(got) != 0 {
+		t.Errorf("evicted %v, want none", got)
+	}
+	if n := c.Len(); n != 0 {
+		t.Errorf("Len() = %d, want 0", n)
+	}
+}
+
+func TestSweep(t *testing.T) {
+	c, clock, r := newCache()
+	for i, k := range []string{"a", "b", "c", "d", "e"} {
+		c.Set(k, i, time.Duration(i+1)*time.Second)
+	}
+	if got := c.Sweep(epoch.Add(time.Second / 2)); got != 0 {
+		t.Errorf("Sweep() = %d before any expiry, want 0", got)
+	}
+	clock.Advance(3 * time.Second)
+	if got := c.Sweep(clock.Now()); got != 3 {
+		t.Errorf("Sweep() = %d, want 3", got)
+	}
+	if got := r.get(); !slices.Equal(got, []string{"a", "b", "c"}) {
+		t.Errorf("evicted %v, want [a b c]", got)
+	}
+	if n := c.Len(); n != 2 {
+		t.Errorf("Len() = %d, want 2", n)
+	}
+}
+
+func TestJanitor(t *testing.T) {
+	clock := heap.NewFakeClock(epoch)
+	r := &recorder{ch: make(chan string, 1)}
+	c := New(&Options[string, int]{Clock: clock, OnEvict: r.onEvict})
+	c.Set("a", 1, time.Second)
+	c.StartJanitor(time.Minute)
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("second StartJanitor did not panic")
+			}
+		}()
+		c.StartJanitor(time.Minute)
+	}()
+
+	waitTimers(t, clock, 1)
+	clock.Advance(time.Minute)
+	select {
+	case k := <-r.ch:
+		if k != "a" {
+			t.Errorf("janitor evicted %q, want a", k)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("janitor did not sweep")
+	}
+
+	waitTimers(t, clock, 1) // the janitor waits for its next interval
+	c.Close()
+	if n := clock.Timers(); n != 0 {
+		t.Errorf("FakeClock has %d timers after Close, want 0", n)
+	}
+	c.Close() // closing again is a no-op
+	c.StartJanitor(time.Minute)
+	c.Close()
+}
+
+// waitTimers waits until c has n timers waiting to fire.
+func waitTimers(t *testing.T, c *heap.FakeClock, n int) {
+	t.Helper()
+	deadline := time.Now().Add(10 * time.Second)
+	for c.Timers() != n {
+		if time.Now().After(deadline) {
+			t.Fatalf("FakeClock has %d timers, want %d", c.Timers(), n)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}